@@ -1,13 +1,56 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/bonefabric/htop/internal/export"
+	"github.com/bonefabric/htop/internal/metrics"
 	"github.com/bonefabric/htop/internal/ui"
 )
 
+// version задается при сборке: go build -ldflags "-X main.version=1.2.3"
+var version = "dev"
+
+// errExit сигнализирует, что программа уже выполнила действие (--help, --version)
+// и должна завершиться без запуска интерфейса
+var errExit = errors.New("exit")
+
 func main() {
-	dashboard, err := ui.NewDashboard()
+	opts, err := parseOptions(os.Args[1:], os.Stdout)
+	if errors.Is(err, errExit) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "htop: %v\n", err)
+		os.Exit(2)
+	}
+
+	if opts.MetricsAddr != "" {
+		if err := ui.RunMetrics(opts); err != nil {
+			fmt.Fprintf(os.Stderr, "htop: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if opts.Batch {
+		if err := ui.RunBatch(os.Stdout, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "htop: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	dashboard, err := ui.NewDashboard(opts)
 	if err != nil {
 		log.Fatalf("Failed to create dashboard: %v", err)
 	}
@@ -15,4 +58,168 @@ func main() {
 	if err := dashboard.Run(); err != nil {
 		log.Fatalf("Error running dashboard: %v", err)
 	}
-} 
\ No newline at end of file
+}
+
+// parseOptions разбирает аргументы командной строки в параметры запуска.
+// Справка и версия выводятся в out, после чего возвращается errExit.
+func parseOptions(args []string, out io.Writer) (ui.Options, error) {
+	var (
+		opts        ui.Options
+		delay       int
+		pids        string
+		noColor     bool
+		showHelp    bool
+		showVersion bool
+	)
+
+	fs := flag.NewFlagSet("htop", flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.IntVar(&delay, "d", 0, "")
+	fs.IntVar(&delay, "delay", 0, "")
+	fs.StringVar(&opts.User, "u", "", "")
+	fs.StringVar(&opts.User, "user", "", "")
+	fs.StringVar(&pids, "p", "", "")
+	fs.StringVar(&pids, "pid", "", "")
+	fs.StringVar(&opts.SortColumn, "s", "", "")
+	fs.StringVar(&opts.SortColumn, "sort-key", "", "")
+	fs.BoolVar(&opts.Tree, "t", false, "")
+	fs.BoolVar(&opts.Tree, "tree", false, "")
+	fs.BoolVar(&noColor, "C", false, "")
+	fs.BoolVar(&noColor, "no-color", false, "")
+	fs.BoolVar(&opts.Batch, "b", false, "")
+	fs.BoolVar(&opts.Batch, "batch", false, "")
+	fs.IntVar(&opts.Iterations, "n", 0, "")
+	fs.IntVar(&opts.Iterations, "iterations", 0, "")
+	fs.StringVar(&opts.Export, "export", "", "")
+	fs.StringVar(&opts.ExportFields, "fields", "", "")
+	fs.StringVar(&opts.ProcRoot, "procfs", "", "")
+	fs.StringVar(&opts.RecordPath, "record", "", "")
+	fs.StringVar(&opts.ReplayPath, "replay", "", "")
+	fs.StringVar(&opts.MetricsAddr, "metrics", "", "")
+	fs.IntVar(&opts.MetricsTop, "metrics-top", 0, "")
+	fs.StringVar(&opts.ConfigPath, "config", "", "")
+	fs.BoolVar(&showHelp, "h", false, "")
+	fs.BoolVar(&showHelp, "help", false, "")
+	fs.BoolVar(&showVersion, "V", false, "")
+	fs.BoolVar(&showVersion, "version", false, "")
+	fs.Usage = func() { printUsage(out) }
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return opts, errExit
+		}
+		return opts, err
+	}
+	if showHelp {
+		printUsage(out)
+		return opts, errExit
+	}
+	if showVersion {
+		fmt.Fprintf(out, "htop %s\n", version)
+		return opts, errExit
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	if delay < 0 {
+		return opts, fmt.Errorf("invalid delay %d: must be positive", delay)
+	}
+	// Как в htop, задержка указывается в десятых долях секунды
+	opts.Delay = time.Duration(delay) * 100 * time.Millisecond
+
+	if opts.RecordPath != "" {
+		if opts.Export != "" {
+			return opts, errors.New("--record cannot be combined with --export")
+		}
+		// Запись ведется без интерфейса
+		opts.Batch = true
+	}
+	if opts.ReplayPath != "" {
+		if opts.Batch || opts.Export != "" || opts.ProcRoot != "" {
+			return opts, errors.New("--replay cannot be combined with -b, --export, --record or --procfs")
+		}
+	}
+	if opts.MetricsAddr != "" {
+		if opts.Batch || opts.Export != "" || opts.ReplayPath != "" || opts.Iterations > 0 {
+			return opts, errors.New("--metrics cannot be combined with -b, -n, --export, --record or --replay")
+		}
+	}
+	if opts.MetricsTop != 0 {
+		if opts.MetricsAddr == "" {
+			return opts, errors.New("--metrics-top requires --metrics")
+		}
+		if opts.MetricsTop < 0 || opts.MetricsTop > metrics.MaxTopN {
+			return opts, fmt.Errorf("invalid --metrics-top %d: must be between 1 and %d", opts.MetricsTop, metrics.MaxTopN)
+		}
+	}
+	if opts.Export != "" {
+		if _, err := export.ParseFormat(opts.Export); err != nil {
+			return opts, err
+		}
+		// Выгрузка работает только без интерфейса
+		opts.Batch = true
+	}
+	if opts.ExportFields != "" {
+		if opts.Export == "" {
+			return opts, errors.New("--fields requires --export")
+		}
+		if _, err := export.ParseFields(opts.ExportFields); err != nil {
+			return opts, err
+		}
+	}
+	if opts.Iterations < 0 {
+		return opts, fmt.Errorf("invalid iterations %d: must be positive", opts.Iterations)
+	}
+	if opts.Iterations > 0 && !opts.Batch {
+		return opts, errors.New("-n requires batch mode (-b)")
+	}
+
+	// Фильтр сравнивается с именем владельца процесса. Запись и procfs могут
+	// быть сняты на другой машине, где есть пользователи, неизвестные здесь,
+	// поэтому имя проверяется только для данных этой машины.
+	if opts.User != "" && opts.ProcRoot == "" && opts.ReplayPath == "" {
+		if _, err := user.Lookup(opts.User); err != nil {
+			return opts, fmt.Errorf("unknown user %q", opts.User)
+		}
+	}
+
+	if pids != "" {
+		for _, field := range strings.Split(pids, ",") {
+			pid, err := strconv.ParseInt(strings.TrimSpace(field), 10, 32)
+			if err != nil || pid < 0 {
+				return opts, fmt.Errorf("invalid PID %q in -p", field)
+			}
+			opts.PIDs = append(opts.PIDs, int32(pid))
+		}
+	}
+
+	opts.Monochrome = noColor
+	return opts, nil
+}
+
+// printUsage выводит справку по параметрам запуска
+func printUsage(out io.Writer) {
+	fmt.Fprint(out, `Usage: htop [options]
+
+Options:
+  -d, --delay=DELAY        Delay between updates, in tenths of seconds
+  -u, --user=USER          Show only processes of a given user
+  -p, --pid=PID[,PID...]   Show only the given PIDs
+  -s, --sort-key=COLUMN    Sort by COLUMN (PID, CPU, MEM, TIME, USER, ...)
+  -t, --tree               Show the tree view
+  -C, --no-color           Use a monochrome color scheme
+  -b, --batch              Print snapshots to stdout instead of the interface
+  -n, --iterations=N       Exit after N updates in batch mode
+      --export=FORMAT      Print snapshots as json, ndjson or csv (implies -b)
+      --fields=FIELD[,...] Process fields to export (pid, user, cpu_percent, ...)
+      --procfs=PATH        Read processes, CPU and memory from a procfs at PATH
+      --record=FILE        Record snapshots to FILE without the interface (implies -b)
+      --replay=FILE        Play back a recording made with --record
+      --metrics=ADDR       Serve Prometheus metrics on ADDR/metrics instead of the interface
+      --metrics-top=N      Export metrics of the top N processes by CPU and by memory
+      --config=PATH        Read and store settings in PATH
+  -h, --help               Print this help screen
+  -V, --version            Print version info
+`)
+}
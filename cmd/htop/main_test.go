@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os/user"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseOptions(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Skipf("Cannot determine current user: %v", err)
+	}
+
+	var out bytes.Buffer
+	opts, err := parseOptions([]string{
+		"-d", "15", "-u", current.Username, "-p", "1, 42", "-s", "mem", "-t", "--no-color", "--config", "/tmp/htop.json",
+	}, &out)
+	if err != nil {
+		t.Fatalf("parseOptions() вернула ошибку: %v", err)
+	}
+
+	if opts.Delay != 1500*time.Millisecond {
+		t.Errorf("Expected delay 1.5s, got %v", opts.Delay)
+	}
+	if opts.User != current.Username {
+		t.Errorf("Expected user %q, got %q", current.Username, opts.User)
+	}
+	if len(opts.PIDs) != 2 || opts.PIDs[0] != 1 || opts.PIDs[1] != 42 {
+		t.Errorf("Expected PIDs [1 42], got %v", opts.PIDs)
+	}
+	if opts.SortColumn != "mem" || !opts.Tree || !opts.Monochrome || opts.ConfigPath != "/tmp/htop.json" {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+}
+
+func TestParseOptions_LongForms(t *testing.T) {
+	opts, err := parseOptions([]string{"--delay=5", "--sort-key=PID", "--tree", "-C"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseOptions() вернула ошибку: %v", err)
+	}
+	if opts.Delay != 500*time.Millisecond || opts.SortColumn != "PID" || !opts.Tree || !opts.Monochrome {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+}
+
+func TestParseOptions_Batch(t *testing.T) {
+	opts, err := parseOptions([]string{"-b", "-n", "3"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseOptions() вернула ошибку: %v", err)
+	}
+	if !opts.Batch || opts.Iterations != 3 {
+		t.Errorf("Expected batch mode with 3 iterations, got %+v", opts)
+	}
+}
+
+func TestParseOptions_Export(t *testing.T) {
+	opts, err := parseOptions([]string{"--export=ndjson", "--fields", "pid,cpu_percent", "-n", "1"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseOptions() вернула ошибку: %v", err)
+	}
+	if !opts.Batch || opts.Export != "ndjson" || opts.ExportFields != "pid,cpu_percent" || opts.Iterations != 1 {
+		t.Errorf("Expected export in batch mode, got %+v", opts)
+	}
+}
+
+func TestParseOptions_RecordAndReplay(t *testing.T) {
+	opts, err := parseOptions([]string{"--record=night.rec", "-d", "50"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseOptions() вернула ошибку: %v", err)
+	}
+	if !opts.Batch || opts.RecordPath != "night.rec" {
+		t.Errorf("Expected recording in batch mode, got %+v", opts)
+	}
+
+	opts, err = parseOptions([]string{"--replay", "night.rec"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseOptions() вернула ошибку: %v", err)
+	}
+	if opts.Batch || opts.ReplayPath != "night.rec" {
+		t.Errorf("Expected interactive replay, got %+v", opts)
+	}
+}
+
+func TestParseOptions_Metrics(t *testing.T) {
+	opts, err := parseOptions([]string{"--metrics=:9256", "--metrics-top=10", "-u", "root"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseOptions() вернула ошибку: %v", err)
+	}
+	if opts.MetricsAddr != ":9256" || opts.MetricsTop != 10 || opts.Batch {
+		t.Errorf("Unexpected metrics options: %+v", opts)
+	}
+}
+
+func TestParseOptions_Defaults(t *testing.T) {
+	opts, err := parseOptions(nil, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseOptions() вернула ошибку: %v", err)
+	}
+	if opts.Delay != 0 || opts.Tree || opts.Monochrome || len(opts.PIDs) != 0 {
+		t.Errorf("Expected zero options, got %+v", opts)
+	}
+}
+
+func TestParseOptions_HelpAndVersion(t *testing.T) {
+	for _, arg := range []string{"--help", "-h"} {
+		var out bytes.Buffer
+		_, err := parseOptions([]string{arg}, &out)
+		if !errors.Is(err, errExit) {
+			t.Errorf("Expected errExit for %s, got %v", arg, err)
+		}
+		if !strings.Contains(out.String(), "Usage: htop") || !strings.Contains(out.String(), "--sort-key") {
+			t.Errorf("Expected usage for %s, got %q", arg, out.String())
+		}
+	}
+
+	var out bytes.Buffer
+	_, err := parseOptions([]string{"--version"}, &out)
+	if !errors.Is(err, errExit) {
+		t.Errorf("Expected errExit for --version, got %v", err)
+	}
+	if out.String() != "htop "+version+"\n" {
+		t.Errorf("Unexpected version output: %q", out.String())
+	}
+}
+
+func TestParseOptions_UserOfAnotherHost(t *testing.T) {
+	// Пользователь есть только на машине, где снимались данные
+	for _, args := range [][]string{
+		{"--replay=a.rec", "-u", "no-such-user-for-htop-tests"},
+		{"--procfs=/mnt/proc", "-u", "no-such-user-for-htop-tests"},
+	} {
+		opts, err := parseOptions(args, &bytes.Buffer{})
+		if err != nil {
+			t.Errorf("parseOptions(%q) вернула ошибку: %v", args, err)
+			continue
+		}
+		if opts.User != "no-such-user-for-htop-tests" {
+			t.Errorf("Expected the user filter to be kept, got %q", opts.User)
+		}
+	}
+}
+
+func TestParseOptions_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"Bad PID", []string{"-p", "1,abc"}, `invalid PID "abc"`},
+		{"Negative delay", []string{"-d", "-1"}, "invalid delay"},
+		{"Unknown user", []string{"-u", "no-such-user-for-htop-tests"}, "unknown user"},
+		{"Unknown flag", []string{"--frobnicate"}, "flag provided but not defined"},
+		{"Negative iterations", []string{"-b", "-n", "-1"}, "invalid iterations"},
+		{"Iterations without batch", []string{"-n", "2"}, "requires batch mode"},
+		{"Unknown export format", []string{"--export=xml"}, `unknown export format "xml"`},
+		{"Unknown export field", []string{"--export=csv", "--fields=pid,foo"}, `unknown export field "foo"`},
+		{"Fields without export", []string{"--fields=pid"}, "requires --export"},
+		{"Record with export", []string{"--record=a.rec", "--export=json"}, "cannot be combined"},
+		{"Replay in batch mode", []string{"--replay=a.rec", "-b"}, "cannot be combined"},
+		{"Metrics in batch mode", []string{"--metrics=:9256", "-b"}, "cannot be combined"},
+		{"Metrics with replay", []string{"--metrics=:9256", "--replay=a.rec"}, "cannot be combined"},
+		{"Top without metrics", []string{"--metrics-top=5"}, "requires --metrics"},
+		{"Top out of range", []string{"--metrics=:9256", "--metrics-top=1000"}, "invalid --metrics-top"},
+		{"Positional argument", []string{"extra"}, `unexpected argument "extra"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.args, &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Config содержит настройки приложения, сохраняемые между запусками
+type Config struct {
+	RefreshInterval Duration `json:"refresh_interval"`
+	CPUMode         string   `json:"cpu_mode"` // "per-core" или "total"
+	Layout          Layout   `json:"layout"`
+	Columns         []string `json:"columns"`
+	Colors          Colors   `json:"colors"`
+	Sort            Sort     `json:"sort"`
+	TreeView        bool     `json:"tree_view"`
+	FavoriteSignals []string `json:"favorite_signals"` // Сигналы, закрепленные вверху меню
+}
+
+// Layout описывает расположение и вид индикаторов
+type Layout struct {
+	CPUColumns int      `json:"cpu_columns"` // минимальное количество столбцов индикаторов CPU
+	GaugeWidth int      `json:"gauge_width"` // ширина одного индикатора CPU
+	MeterStyle string   `json:"meter_style"` // вид индикаторов CPU и памяти
+	History    Duration `json:"history"`     // за какой период индикаторы показывают историю
+}
+
+// Colors описывает цветовую индикацию нагрузки: пороги в процентах
+// и цвета для каждого уровня
+type Colors struct {
+	MediumThreshold   int    `json:"medium_threshold"`
+	HighThreshold     int    `json:"high_threshold"`
+	CriticalThreshold int    `json:"critical_threshold"`
+	Low               string `json:"low"`
+	Medium            string `json:"medium"`
+	High              string `json:"high"`
+	Critical          string `json:"critical"`
+}
+
+// Sort описывает сортировку списка процессов
+type Sort struct {
+	Column     string `json:"column"`
+	Descending bool   `json:"descending"`
+}
+
+// Duration - time.Duration, которая хранится в файле строкой вида "1s" или "500ms"
+type Duration time.Duration
+
+// MarshalJSON записывает длительность строкой
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON читает длительность из строки
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"1s\": %v", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// CPU modes
+const (
+	CPUModePerCore = "per-core"
+	CPUModeTotal   = "total"
+)
+
+// Meter styles
+const (
+	MeterGauge     = "gauge"     // текущее значение полосой
+	MeterSparkline = "sparkline" // история в одну строку символами ▁▂▃▄▅▆▇█
+	MeterChart     = "chart"     // история линейным графиком
+)
+
+// MeterStyles - виды индикаторов в порядке переключения
+var MeterStyles = []string{MeterGauge, MeterSparkline, MeterChart}
+
+// ColorNames - цвета, доступные в терминале
+var ColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// Default возвращает настройки по умолчанию
+func Default() Config {
+	return Config{
+		RefreshInterval: Duration(time.Second),
+		CPUMode:         CPUModePerCore,
+		Layout: Layout{
+			CPUColumns: 2,
+			GaugeWidth: 50,
+			MeterStyle: MeterGauge,
+			History:    Duration(5 * time.Minute),
+		},
+		Columns: []string{
+			"PID", "USER", "PRIORITY", "NICE", "IO_PRIORITY", "VIRT", "RES", "STATE", "CPU", "MEM", "TIME", "START", "THREADS", "COMMAND",
+		},
+		Colors: Colors{
+			MediumThreshold:   50,
+			HighThreshold:     70,
+			CriticalThreshold: 90,
+			Low:               "green",
+			Medium:            "magenta",
+			High:              "yellow",
+			Critical:          "red",
+		},
+		Sort: Sort{
+			Column:     "CPU",
+			Descending: true,
+		},
+		FavoriteSignals: []string{"SIGTERM", "SIGKILL", "SIGINT", "SIGHUP"},
+	}
+}
+
+// DefaultPath возвращает путь к файлу настроек: $XDG_CONFIG_HOME/htop/config.json,
+// или ~/.config/htop/config.json, если переменная не задана
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate config directory: %v", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "htop", "config.json"), nil
+}
+
+// Load читает настройки из файла. Отсутствующий файл не является ошибкой -
+// возвращаются настройки по умолчанию. Поля, не указанные в файле, также
+// получают значения по умолчанию.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config %s: %v", path, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// Save записывает настройки в файл, создавая каталог при необходимости.
+// Файл заменяется атомарно, чтобы прерванная запись не испортила настройки.
+func Save(path string, cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("refusing to save invalid config: %v", err)
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %v", err)
+	}
+	data = append(data, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write config: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write config: %v", err)
+	}
+	return nil
+}
+
+// Validate проверяет значения настроек и возвращает все найденные ошибки
+// с указанием поля
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if d := time.Duration(c.RefreshInterval); d < 100*time.Millisecond || d > time.Hour {
+		fail("refresh_interval", "must be between 100ms and 1h, got %v", d)
+	}
+	if c.CPUMode != CPUModePerCore && c.CPUMode != CPUModeTotal {
+		fail("cpu_mode", "must be %q or %q, got %q", CPUModePerCore, CPUModeTotal, c.CPUMode)
+	}
+	if c.Layout.CPUColumns < 1 || c.Layout.CPUColumns > 16 {
+		fail("layout.cpu_columns", "must be between 1 and 16, got %d", c.Layout.CPUColumns)
+	}
+	if c.Layout.GaugeWidth < 10 {
+		fail("layout.gauge_width", "must be at least 10, got %d", c.Layout.GaugeWidth)
+	}
+	if !slices.Contains(MeterStyles, c.Layout.MeterStyle) {
+		fail("layout.meter_style", "must be one of %s, got %q", strings.Join(MeterStyles, ", "), c.Layout.MeterStyle)
+	}
+	if d := time.Duration(c.Layout.History); d < 10*time.Second || d > 24*time.Hour {
+		fail("layout.history", "must be between 10s and 24h, got %v", d)
+	}
+	if len(c.Columns) == 0 {
+		fail("columns", "at least one column is required")
+	}
+	if c.Sort.Column == "" {
+		fail("sort.column", "must not be empty")
+	}
+	// Имена не сверяются со списком сигналов: файл может быть общим для разных ОС,
+	// а сигналы, которых нет на текущей, интерфейс пропускает
+	for i, name := range c.FavoriteSignals {
+		if !strings.HasPrefix(name, "SIG") || len(name) == len("SIG") {
+			fail("favorite_signals", "expected signal name like \"SIGTERM\", got %q", name)
+		} else if slices.Contains(c.FavoriteSignals[:i], name) {
+			fail("favorite_signals", "duplicate signal %q", name)
+		}
+	}
+
+	t := c.Colors
+	if !(0 < t.MediumThreshold && t.MediumThreshold < t.HighThreshold &&
+		t.HighThreshold < t.CriticalThreshold && t.CriticalThreshold <= 100) {
+		fail("colors", "thresholds must satisfy 0 < medium < high < critical <= 100, got %d/%d/%d",
+			t.MediumThreshold, t.HighThreshold, t.CriticalThreshold)
+	}
+	for _, color := range []struct{ field, name string }{
+		{"colors.low", t.Low},
+		{"colors.medium", t.Medium},
+		{"colors.high", t.High},
+		{"colors.critical", t.Critical},
+	} {
+		if !isColorName(color.name) {
+			fail(color.field, "unknown color %q, expected one of %s", color.name, strings.Join(ColorNames, ", "))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// isColorName проверяет, что цвет есть в ColorNames
+func isColorName(name string) bool {
+	for _, n := range ColorNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
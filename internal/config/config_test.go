@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Errorf("Default config is invalid: %v", err)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath() вернула ошибку: %v", err)
+	}
+	if path != filepath.Join("/tmp/xdg", "htop", "config.json") {
+		t.Errorf("Unexpected config path: %s", path)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "/home/test")
+	path, err = DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath() вернула ошибку: %v", err)
+	}
+	if path != filepath.Join("/home/test", ".config", "htop", "config.json") {
+		t.Errorf("Unexpected fallback config path: %s", path)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Expected no error for missing file, got %v", err)
+	}
+	if time.Duration(cfg.RefreshInterval) != time.Second {
+		t.Errorf("Expected default refresh interval, got %v", time.Duration(cfg.RefreshInterval))
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.json")
+
+	cfg := Default()
+	cfg.RefreshInterval = Duration(2500 * time.Millisecond)
+	cfg.Columns = []string{"PID", "COMMAND"}
+	cfg.Sort = Sort{Column: "MEM", Descending: false}
+	cfg.TreeView = true
+	cfg.FavoriteSignals = []string{"SIGUSR1", "SIGRTMIN+2"}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save() вернула ошибку: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read saved config: %v", err)
+	}
+	if !strings.Contains(string(data), `"refresh_interval": "2.5s"`) {
+		t.Errorf("Expected refresh interval as string, got:\n%s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() вернула ошибку: %v", err)
+	}
+	if time.Duration(loaded.RefreshInterval) != 2500*time.Millisecond ||
+		strings.Join(loaded.Columns, ",") != "PID,COMMAND" ||
+		loaded.Sort != cfg.Sort || !loaded.TreeView ||
+		strings.Join(loaded.FavoriteSignals, ",") != "SIGUSR1,SIGRTMIN+2" {
+		t.Errorf("Loaded config differs from saved: %+v", loaded)
+	}
+}
+
+func TestLoad_PartialFileKeepsDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"tree_view": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() вернула ошибку: %v", err)
+	}
+	if !cfg.TreeView || cfg.Layout.GaugeWidth != 50 || len(cfg.Columns) == 0 {
+		t.Errorf("Expected defaults for missing fields, got %+v", cfg)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"Malformed JSON", `{"tree_view": }`, "invalid character"},
+		{"Unknown field", `{"refresh": "1s"}`, `unknown field "refresh"`},
+		{"Bad duration", `{"refresh_interval": "fast"}`, "invalid duration"},
+		{"Duration as number", `{"refresh_interval": 1000}`, "duration must be a string"},
+		{"Interval too short", `{"refresh_interval": "10ms"}`, "refresh_interval: must be between 100ms and 1h"},
+		{"Bad CPU mode", `{"cpu_mode": "avg"}`, `cpu_mode: must be "per-core" or "total"`},
+		{"Zero CPU columns", `{"layout": {"cpu_columns": 0, "gauge_width": 50}}`, "layout.cpu_columns"},
+		{"Unknown meter style", `{"layout": {"meter_style": "dial"}}`, `layout.meter_style: must be one of gauge, sparkline, chart, got "dial"`},
+		{"History too long", `{"layout": {"history": "48h"}}`, "layout.history: must be between 10s and 24h"},
+		{"Empty columns", `{"columns": []}`, "columns: at least one column is required"},
+		{"Bad favorite signal", `{"favorite_signals": ["TERM"]}`, `favorite_signals: expected signal name like "SIGTERM", got "TERM"`},
+		{"Duplicate favorite signal", `{"favorite_signals": ["SIGHUP", "SIGHUP"]}`, `favorite_signals: duplicate signal "SIGHUP"`},
+		{"Thresholds out of order", `{"colors": {"medium_threshold": 80, "high_threshold": 70, "critical_threshold": 90,
+			"low": "green", "medium": "magenta", "high": "yellow", "critical": "red"}}`, "0 < medium < high < critical"},
+		{"Unknown color", `{"colors": {"medium_threshold": 50, "high_threshold": 70, "critical_threshold": 90,
+			"low": "green", "medium": "purple", "high": "yellow", "critical": "red"}}`, `colors.medium: unknown color "purple"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+			if !strings.Contains(err.Error(), path) {
+				t.Errorf("Expected error to mention config path, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	cfg := Default()
+	cfg.CPUMode = ""
+	cfg.Layout.GaugeWidth = 1
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	if !strings.Contains(err.Error(), "cpu_mode") || !strings.Contains(err.Error(), "layout.gauge_width") {
+		t.Errorf("Expected both errors to be reported, got %q", err.Error())
+	}
+}
+
+func TestSave_RejectsInvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := Default()
+	cfg.Columns = nil
+	if err := Save(path, cfg); err == nil {
+		t.Error("Expected error when saving invalid config")
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("Invalid config must not be written")
+	}
+}
@@ -0,0 +1,217 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// SchemaVersion - версия формата выгрузки. Увеличивается при любом
+// несовместимом изменении: переименовании или удалении поля, смене типа.
+// Добавление новых полей версию не меняет.
+const SchemaVersion = 1
+
+// Format - формат выгрузки
+type Format string
+
+const (
+	// FormatJSON - каждый снимок отдельным JSON-документом с отступами
+	FormatJSON Format = "json"
+	// FormatNDJSON - каждый снимок одной строкой (newline-delimited JSON)
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV - строка на каждый процесс каждого снимка, без данных CPU и памяти
+	FormatCSV Format = "csv"
+)
+
+// Formats - доступные форматы выгрузки
+var Formats = []Format{FormatJSON, FormatNDJSON, FormatCSV}
+
+// ParseFormat возвращает формат по имени
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown export format %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// Snapshot - данные одного обновления: то же, что видно на экране
+type Snapshot struct {
+	Time      time.Time
+	CPU       []float64 // загрузка каждого ядра в процентах
+	Memory    *mem.VirtualMemoryStat
+	Processes []system.ProcessInfo
+}
+
+// document - снимок в формате выгрузки
+type document struct {
+	SchemaVersion int       `json:"schema_version"`
+	Time          time.Time `json:"time"`
+	CPU           []float64 `json:"cpu"`
+	Memory        *memory   `json:"memory,omitempty"`
+	Processes     []record  `json:"processes"`
+}
+
+// memory - данные о памяти в формате выгрузки. Поля перечислены явно,
+// чтобы схема не зависела от тегов gopsutil.
+type memory struct {
+	Total       uint64  `json:"total"`
+	Available   uint64  `json:"available"`
+	Used        uint64  `json:"used"`
+	Free        uint64  `json:"free"`
+	UsedPercent float64 `json:"used_percent"`
+	Buffers     uint64  `json:"buffers"`
+	Cached      uint64  `json:"cached"`
+	SwapTotal   uint64  `json:"swap_total"`
+	SwapFree    uint64  `json:"swap_free"`
+}
+
+// record - процесс, сериализуемый только с выбранными полями в их порядке
+type record struct {
+	fields  []Field
+	process system.ProcessInfo
+}
+
+// MarshalJSON записывает поля процесса в порядке выбора
+func (r record) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, f := range r.fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(f.Name)
+		value, err := json.Marshal(f.value(r.process))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", f.Name, err)
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+// Encoder записывает снимки в выбранном формате
+type Encoder struct {
+	format      Format
+	fields      []Field
+	json        *json.Encoder
+	csv         *csv.Writer
+	wroteHeader bool
+}
+
+// NewEncoder создает Encoder, записывающий снимки в w. Если fields пуст,
+// выгружаются все поля процессов.
+func NewEncoder(w io.Writer, format Format, fields []Field) *Encoder {
+	if len(fields) == 0 {
+		fields = Fields
+	}
+	e := &Encoder{format: format, fields: fields}
+	switch format {
+	case FormatCSV:
+		e.csv = csv.NewWriter(w)
+	default:
+		e.json = json.NewEncoder(w)
+		if format == FormatJSON {
+			e.json.SetIndent("", "  ")
+		}
+	}
+	return e
+}
+
+// Encode записывает один снимок
+func (e *Encoder) Encode(s Snapshot) error {
+	if e.csv != nil {
+		return e.encodeCSV(s)
+	}
+
+	doc := document{
+		SchemaVersion: SchemaVersion,
+		Time:          s.Time,
+		CPU:           s.CPU,
+		Processes:     make([]record, len(s.Processes)),
+	}
+	if doc.CPU == nil {
+		doc.CPU = []float64{}
+	}
+	if m := s.Memory; m != nil {
+		doc.Memory = &memory{
+			Total:       m.Total,
+			Available:   m.Available,
+			Used:        m.Used,
+			Free:        m.Free,
+			UsedPercent: m.UsedPercent,
+			Buffers:     m.Buffers,
+			Cached:      m.Cached,
+			SwapTotal:   m.SwapTotal,
+			SwapFree:    m.SwapFree,
+		}
+	}
+	for i, p := range s.Processes {
+		doc.Processes[i] = record{fields: e.fields, process: p}
+	}
+	return e.json.Encode(doc)
+}
+
+// encodeCSV записывает процессы снимка строками CSV. Первая колонка - время
+// снимка, заголовок пишется один раз.
+func (e *Encoder) encodeCSV(s Snapshot) error {
+	if !e.wroteHeader {
+		header := make([]string, 0, len(e.fields)+1)
+		header = append(header, "time")
+		for _, f := range e.fields {
+			header = append(header, f.Name)
+		}
+		if err := e.csv.Write(header); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+
+	at := s.Time.Format(time.RFC3339Nano)
+	row := make([]string, len(e.fields)+1)
+	for _, p := range s.Processes {
+		row[0] = at
+		for i, f := range e.fields {
+			row[i+1] = csvValue(f.value(p))
+		}
+		if err := e.csv.Write(row); err != nil {
+			return err
+		}
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+// csvValue форматирует значение поля для CSV
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
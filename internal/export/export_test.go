@@ -0,0 +1,125 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+func testSnapshot() Snapshot {
+	return Snapshot{
+		Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		CPU:  []float64{12.5, 50},
+		Memory: &mem.VirtualMemoryStat{
+			Total: 1000, Available: 600, Used: 400, Free: 300, UsedPercent: 40,
+		},
+		Processes: []system.ProcessInfo{
+			{PID: 1, Name: "init", User: "root", CPU: 0.5, Memory: 0.1, CPUTime: 1500 * time.Millisecond},
+			{PID: 42, Name: "vim", User: "alice", Cmdline: `vim "a, b".txt`,
+				StartTime: time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"json", "NDJSON", "csv"} {
+		if _, err := ParseFormat(name); err != nil {
+			t.Errorf("ParseFormat(%q) вернула ошибку: %v", name, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil || !strings.Contains(err.Error(), "json, ndjson, csv") {
+		t.Errorf("Expected error listing formats, got %v", err)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields("")
+	if err != nil || len(fields) != len(Fields) {
+		t.Errorf("Expected all fields for empty list, got %d, %v", len(fields), err)
+	}
+
+	fields, err = ParseFields(" USER, pid,user")
+	if err != nil {
+		t.Fatalf("ParseFields() вернула ошибку: %v", err)
+	}
+	if len(fields) != 2 || fields[0].Name != "user" || fields[1].Name != "pid" {
+		t.Errorf("Expected [user pid], got %v", fields)
+	}
+
+	if _, err := ParseFields("pid,bogus"); err == nil || !strings.Contains(err.Error(), `"bogus"`) {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+}
+
+func TestEncoder_NDJSON(t *testing.T) {
+	fields, _ := ParseFields("pid,user,cpu_time,start_time")
+	var out bytes.Buffer
+	encoder := NewEncoder(&out, FormatNDJSON, fields)
+	for i := 0; i < 2; i++ {
+		if err := encoder.Encode(testSnapshot()); err != nil {
+			t.Fatalf("Encode() вернул ошибку: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected one line per snapshot, got %d:\n%s", len(lines), out.String())
+	}
+	want := `{"schema_version":1,"time":"2024-05-01T12:00:00Z","cpu":[12.5,50],` +
+		`"memory":{"total":1000,"available":600,"used":400,"free":300,"used_percent":40,"buffers":0,"cached":0,"swap_total":0,"swap_free":0},` +
+		`"processes":[{"pid":1,"user":"root","cpu_time":1.5,"start_time":null},` +
+		`{"pid":42,"user":"alice","cpu_time":0,"start_time":"2024-05-01T11:00:00Z"}]}`
+	if lines[0] != want {
+		t.Errorf("Unexpected NDJSON line:\n%s\nwant:\n%s", lines[0], want)
+	}
+}
+
+func TestEncoder_JSONAllFields(t *testing.T) {
+	var out bytes.Buffer
+	if err := NewEncoder(&out, FormatJSON, nil).Encode(testSnapshot()); err != nil {
+		t.Fatalf("Encode() вернул ошибку: %v", err)
+	}
+
+	var doc struct {
+		SchemaVersion int              `json:"schema_version"`
+		Processes     []map[string]any `json:"processes"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out.String())
+	}
+	if doc.SchemaVersion != SchemaVersion || len(doc.Processes) != 2 {
+		t.Fatalf("Unexpected document: %+v", doc)
+	}
+	for _, f := range Fields {
+		if _, ok := doc.Processes[0][f.Name]; !ok {
+			t.Errorf("Expected field %q in process", f.Name)
+		}
+	}
+	if !strings.Contains(out.String(), "\n  \"schema_version\"") {
+		t.Error("Expected indented JSON")
+	}
+}
+
+func TestEncoder_CSV(t *testing.T) {
+	fields, _ := ParseFields("pid,mem_percent,cmdline")
+	var out bytes.Buffer
+	encoder := NewEncoder(&out, FormatCSV, fields)
+	for i := 0; i < 2; i++ {
+		if err := encoder.Encode(testSnapshot()); err != nil {
+			t.Fatalf("Encode() вернул ошибку: %v", err)
+		}
+	}
+
+	row1 := "2024-05-01T12:00:00Z,1,0.1,\n"
+	row2 := "2024-05-01T12:00:00Z,42,0,\"vim \"\"a, b\"\".txt\"\n"
+	want := "time,pid,mem_percent,cmdline\n" + row1 + row2 + row1 + row2
+	if out.String() != want {
+		t.Errorf("Unexpected CSV:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// Field - поле процесса в выгрузке. Имена полей входят в схему и не меняются
+// в пределах одной SchemaVersion.
+type Field struct {
+	Name        string
+	Description string
+	value       func(p system.ProcessInfo) any
+}
+
+// Fields - все поля процесса в порядке выгрузки по умолчанию
+var Fields = []Field{
+	{Name: "pid", Description: "Process ID",
+		value: func(p system.ProcessInfo) any { return p.PID }},
+	{Name: "ppid", Description: "Parent process ID",
+		value: func(p system.ProcessInfo) any { return p.PPID }},
+	{Name: "name", Description: "Executable name",
+		value: func(p system.ProcessInfo) any { return p.Name }},
+	{Name: "user", Description: "Process owner",
+		value: func(p system.ProcessInfo) any { return p.User }},
+	{Name: "status", Description: "Process state",
+		value: func(p system.ProcessInfo) any { return p.Status }},
+	{Name: "cpu_percent", Description: "CPU usage over the last interval",
+		value: func(p system.ProcessInfo) any { return p.CPU }},
+	{Name: "mem_percent", Description: "Resident memory share",
+		value: func(p system.ProcessInfo) any { return p.Memory }},
+	{Name: "rss", Description: "Resident memory size in bytes",
+		value: func(p system.ProcessInfo) any { return p.RSS }},
+	{Name: "vsz", Description: "Virtual memory size in bytes",
+		value: func(p system.ProcessInfo) any { return p.VSZ }},
+	{Name: "threads", Description: "Number of threads",
+		value: func(p system.ProcessInfo) any { return p.Threads }},
+	{Name: "priority", Description: "Kernel scheduling priority",
+		value: func(p system.ProcessInfo) any { return p.Priority }},
+	{Name: "nice", Description: "Nice value",
+		value: func(p system.ProcessInfo) any { return p.Nice }},
+	{Name: "start_time", Description: "Start time, empty if unknown",
+		value: func(p system.ProcessInfo) any {
+			if p.StartTime.IsZero() {
+				return nil
+			}
+			return p.StartTime
+		}},
+	{Name: "cpu_time", Description: "Accumulated CPU time in seconds",
+		value: func(p system.ProcessInfo) any { return p.CPUTime.Seconds() }},
+	{Name: "read_bytes", Description: "Bytes read from storage",
+		value: func(p system.ProcessInfo) any { return p.ReadBytes }},
+	{Name: "write_bytes", Description: "Bytes written to storage",
+		value: func(p system.ProcessInfo) any { return p.WriteBytes }},
+	{Name: "cgroup", Description: "Control group path",
+		value: func(p system.ProcessInfo) any { return p.Cgroup }},
+	{Name: "cmdline", Description: "Full command line",
+		value: func(p system.ProcessInfo) any { return p.Cmdline }},
+}
+
+// ParseFields возвращает поля по именам через запятую в указанном порядке.
+// Пустая строка означает все поля.
+func ParseFields(list string) ([]Field, error) {
+	if strings.TrimSpace(list) == "" {
+		return Fields, nil
+	}
+	var fields []Field
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		field, ok := findField(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown export field %q", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// findField возвращает поле по имени
+func findField(name string) (Field, bool) {
+	for _, f := range Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// Format - текстовый формат метрик
+type Format int
+
+const (
+	// FormatPrometheus - текстовый формат Prometheus 0.0.4
+	FormatPrometheus Format = iota
+	// FormatOpenMetrics - формат OpenMetrics 1.0.0
+	FormatOpenMetrics
+)
+
+// ContentType возвращает заголовок Content-Type для формата
+func (f Format) ContentType() string {
+	if f == FormatOpenMetrics {
+		return "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	}
+	return "text/plain; version=0.0.4; charset=utf-8"
+}
+
+// MaxTopN ограничивает количество процессов в метриках: каждый процесс -
+// отдельный набор меток, и без ограничения число рядов в Prometheus
+// растет вместе с числом процессов на машине
+const MaxTopN = 100
+
+// maxNameLength - длина имени процесса в метке name
+const maxNameLength = 32
+
+// label - метка ряда
+type label struct {
+	name, value string
+}
+
+// writer пишет семейства метрик в выбранном формате
+type writer struct {
+	w      *bufio.Writer
+	format Format
+}
+
+// family пишет заголовок семейства. Для счетчиков в OpenMetrics имя
+// семейства указывается без суффикса _total, а в Prometheus - с ним.
+func (w *writer) family(name, typ, help string) {
+	if typ == "counter" && w.format == FormatOpenMetrics {
+		name = strings.TrimSuffix(name, "_total")
+	}
+	w.w.WriteString("# HELP " + name + " " + help + "\n")
+	w.w.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+// sample пишет значение ряда
+func (w *writer) sample(name string, value float64, labels ...label) {
+	w.w.WriteString(name)
+	if len(labels) > 0 {
+		w.w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.w.WriteByte(',')
+			}
+			w.w.WriteString(l.name + `="` + escapeLabel(l.value) + `"`)
+		}
+		w.w.WriteByte('}')
+	}
+	w.w.WriteByte(' ')
+	w.w.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	w.w.WriteByte('\n')
+}
+
+// gauge пишет семейство из одного ряда без меток
+func (w *writer) gauge(name, help string, value float64) {
+	w.family(name, "gauge", help)
+	w.sample(name, value)
+}
+
+// labelEscaper экранирует обратную косую черту, кавычки и перевод строки
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel экранирует значение метки
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+// Write пишет метрики снимка s в out. В метрики процессов попадают topN
+// процессов с наибольшей загрузкой CPU и topN с наибольшей резидентной
+// памятью, но не больше MaxTopN каждых.
+func Write(out io.Writer, s system.Snapshot, topN int, format Format) error {
+	w := &writer{w: bufio.NewWriter(out), format: format}
+
+	w.family("htop_cpu_usage_ratio", "gauge", "CPU core utilisation over the last interval.")
+	for i, percent := range s.CPU {
+		w.sample("htop_cpu_usage_ratio", percent/100, label{"cpu", strconv.Itoa(i)})
+	}
+
+	if m := s.Memory; m != nil {
+		w.gauge("htop_memory_total_bytes", "Total physical memory.", float64(m.Total))
+		w.gauge("htop_memory_used_bytes", "Used physical memory.", float64(m.Used))
+		w.gauge("htop_memory_available_bytes", "Memory available for new allocations.", float64(m.Available))
+		w.gauge("htop_memory_buffers_bytes", "Memory used for block device buffers.", float64(m.Buffers))
+		w.gauge("htop_memory_cached_bytes", "Memory used for the page cache.", float64(m.Cached))
+		w.gauge("htop_swap_total_bytes", "Total swap space.", float64(m.SwapTotal))
+		w.gauge("htop_swap_free_bytes", "Unused swap space.", float64(m.SwapFree))
+	}
+
+	if l := s.Load; l != nil {
+		w.gauge("htop_load1", "1-minute load average.", l.Load1)
+		w.gauge("htop_load5", "5-minute load average.", l.Load5)
+		w.gauge("htop_load15", "15-minute load average.", l.Load15)
+	}
+
+	if s.ProcessErr == nil {
+		w.gauge("htop_processes", "Number of processes.", float64(len(s.Processes)))
+		if top := topProcesses(s.Processes, topN); len(top) > 0 {
+			writeProcesses(w, top)
+		}
+	}
+
+	w.gauge("htop_last_update_timestamp_seconds", "Time of the last data collection.",
+		float64(s.Time.UnixNano())/1e9)
+
+	if format == FormatOpenMetrics {
+		w.w.WriteString("# EOF\n")
+	}
+	return w.w.Flush()
+}
+
+// writeProcesses пишет метрики процессов
+func writeProcesses(w *writer, processes []system.ProcessInfo) {
+	labels := make([][]label, len(processes))
+	for i, p := range processes {
+		name := p.Name
+		if runes := []rune(name); len(runes) > maxNameLength {
+			name = string(runes[:maxNameLength])
+		}
+		labels[i] = []label{{"pid", strconv.Itoa(int(p.PID))}, {"name", name}, {"user", p.User}}
+	}
+
+	w.family("htop_process_cpu_usage_ratio", "gauge", "Process CPU utilisation over the last interval.")
+	for i, p := range processes {
+		w.sample("htop_process_cpu_usage_ratio", p.CPU/100, labels[i]...)
+	}
+	w.family("htop_process_cpu_seconds_total", "counter", "Total user and system CPU time of the process.")
+	for i, p := range processes {
+		w.sample("htop_process_cpu_seconds_total", p.CPUTime.Seconds(), labels[i]...)
+	}
+	w.family("htop_process_resident_memory_bytes", "gauge", "Resident memory size of the process.")
+	for i, p := range processes {
+		w.sample("htop_process_resident_memory_bytes", float64(p.RSS), labels[i]...)
+	}
+	w.family("htop_process_threads", "gauge", "Number of threads of the process.")
+	for i, p := range processes {
+		w.sample("htop_process_threads", float64(p.Threads), labels[i]...)
+	}
+}
+
+// topProcesses возвращает объединение topN процессов по CPU и topN по
+// резидентной памяти, упорядоченное по PID
+func topProcesses(processes []system.ProcessInfo, topN int) []system.ProcessInfo {
+	if topN <= 0 {
+		return nil
+	}
+	if topN > MaxTopN {
+		topN = MaxTopN
+	}
+
+	selected := make(map[int32]system.ProcessInfo, 2*topN)
+	sorted := make([]system.ProcessInfo, len(processes))
+	pick := func(less func(a, b system.ProcessInfo) bool) {
+		copy(sorted, processes)
+		sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+		for _, p := range sorted[:min(topN, len(sorted))] {
+			selected[p.PID] = p
+		}
+	}
+	pick(func(a, b system.ProcessInfo) bool { return a.CPU > b.CPU })
+	pick(func(a, b system.ProcessInfo) bool { return a.RSS > b.RSS })
+
+	top := make([]system.ProcessInfo, 0, len(selected))
+	for _, p := range selected {
+		top = append(top, p)
+	}
+	sort.Slice(top, func(i, j int) bool { return top[i].PID < top[j].PID })
+	return top
+}
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// testSnapshot возвращает снимок с двумя ядрами и тремя процессами
+func testSnapshot() system.Snapshot {
+	return system.Snapshot{
+		Time: time.Unix(1700000000, 500000000),
+		CPU:  []float64{25, 100},
+		Memory: &mem.VirtualMemoryStat{Total: 4096, Used: 1024, Available: 3072,
+			Buffers: 16, Cached: 512, SwapTotal: 2048, SwapFree: 2000},
+		Load: &load.AvgStat{Load1: 0.5, Load5: 0.25, Load15: 1},
+		Processes: []system.ProcessInfo{
+			{PID: 1, Name: "init", User: "root", CPU: 1, RSS: 100, CPUTime: 90 * time.Second, Threads: 1},
+			{PID: 42, Name: `we"ird\name`, User: "alice", CPU: 150, RSS: 10, CPUTime: 1500 * time.Millisecond, Threads: 8},
+			{PID: 7, Name: "cache", User: "bob", CPU: 0, RSS: 9000, Threads: 2},
+		},
+	}
+}
+
+func TestWrite_Prometheus(t *testing.T) {
+	var out bytes.Buffer
+	if err := Write(&out, testSnapshot(), 1, FormatPrometheus); err != nil {
+		t.Fatalf("Write() вернул ошибку: %v", err)
+	}
+	text := out.String()
+
+	for _, want := range []string{
+		"# TYPE htop_cpu_usage_ratio gauge\n" +
+			"htop_cpu_usage_ratio{cpu=\"0\"} 0.25\n" +
+			"htop_cpu_usage_ratio{cpu=\"1\"} 1\n",
+		"htop_memory_total_bytes 4096\n",
+		"htop_memory_available_bytes 3072\n",
+		"htop_swap_free_bytes 2000\n",
+		"htop_load1 0.5\n",
+		"htop_load15 1\n",
+		"htop_processes 3\n",
+		"# TYPE htop_process_cpu_seconds_total counter\n",
+		// Первый по CPU и первый по памяти, в порядке PID
+		"htop_process_cpu_usage_ratio{pid=\"7\",name=\"cache\",user=\"bob\"} 0\n" +
+			"htop_process_cpu_usage_ratio{pid=\"42\",name=\"we\\\"ird\\\\name\",user=\"alice\"} 1.5\n",
+		"htop_process_cpu_seconds_total{pid=\"42\",name=\"we\\\"ird\\\\name\",user=\"alice\"} 1.5\n",
+		"htop_process_resident_memory_bytes{pid=\"7\",name=\"cache\",user=\"bob\"} 9000\n",
+		"htop_last_update_timestamp_seconds 1.7000000005e+09\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected output to contain:\n%s\ngot:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, `pid="1"`) {
+		t.Error("Process outside top-N must not be exported")
+	}
+	if strings.Contains(text, "# EOF") {
+		t.Error("Prometheus text format must not end with # EOF")
+	}
+}
+
+func TestWrite_OpenMetrics(t *testing.T) {
+	var out bytes.Buffer
+	if err := Write(&out, testSnapshot(), 1, FormatOpenMetrics); err != nil {
+		t.Fatalf("Write() вернул ошибку: %v", err)
+	}
+	text := out.String()
+	if !strings.HasSuffix(text, "# EOF\n") {
+		t.Errorf("Expected OpenMetrics output to end with # EOF, got:\n%s", text)
+	}
+	// Семейство счетчика называется без _total, ряд - с ним
+	if !strings.Contains(text, "# TYPE htop_process_cpu_seconds counter\n") ||
+		!strings.Contains(text, "\nhtop_process_cpu_seconds_total{") {
+		t.Errorf("Unexpected counter family:\n%s", text)
+	}
+}
+
+func TestWrite_PartialSnapshot(t *testing.T) {
+	s := testSnapshot()
+	s.Load = nil
+	s.ProcessErr = errors.New("permission denied")
+
+	var out bytes.Buffer
+	if err := Write(&out, s, 10, FormatPrometheus); err != nil {
+		t.Fatalf("Write() вернул ошибку: %v", err)
+	}
+	for _, unexpected := range []string{"htop_load1", "htop_processes", "htop_process_"} {
+		if strings.Contains(out.String(), unexpected) {
+			t.Errorf("Unexpected %s in output:\n%s", unexpected, out.String())
+		}
+	}
+}
+
+func TestTopProcesses(t *testing.T) {
+	processes := make([]system.ProcessInfo, 300)
+	for i := range processes {
+		processes[i] = system.ProcessInfo{PID: int32(i), CPU: float64(i), RSS: uint64(len(processes) - i)}
+	}
+
+	if top := topProcesses(processes, 0); len(top) != 0 {
+		t.Errorf("Expected no processes for topN 0, got %d", len(top))
+	}
+	top := topProcesses(processes, 2)
+	var got []int32
+	for _, p := range top {
+		got = append(got, p.PID)
+	}
+	// 298, 299 - по CPU, 0, 1 - по памяти
+	if want := []int32{0, 1, 298, 299}; len(got) != len(want) || got[0] != 0 || got[1] != 1 || got[2] != 298 || got[3] != 299 {
+		t.Errorf("Expected PIDs %v, got %v", want, got)
+	}
+	if top := topProcesses(processes, 1000); len(top) != 2*MaxTopN {
+		t.Errorf("Expected topN capped at %d per metric, got %d processes", MaxTopN, len(top))
+	}
+}
+
+func TestWrite_TruncatesLongNames(t *testing.T) {
+	s := system.Snapshot{Processes: []system.ProcessInfo{{PID: 1, Name: strings.Repeat("x", 100)}}}
+	var out bytes.Buffer
+	if err := Write(&out, s, 1, FormatPrometheus); err != nil {
+		t.Fatalf("Write() вернул ошибку: %v", err)
+	}
+	if !strings.Contains(out.String(), `name="`+strings.Repeat("x", maxNameLength)+`"`) {
+		t.Errorf("Expected name truncated to %d characters:\n%s", maxNameLength, out.String())
+	}
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// Exporter отдает по HTTP метрики последнего собранного снимка. Снимки
+// собираются в фоне с постоянным интервалом, а не при каждом запросе: иначе
+// загрузка CPU считалась бы за интервал между запросами разных серверов
+// Prometheus.
+type Exporter struct {
+	topN int
+
+	mu   sync.RWMutex
+	last *system.Snapshot
+}
+
+// NewExporter создает Exporter, публикующий метрики topN процессов
+func NewExporter(topN int) *Exporter {
+	return &Exporter{topN: topN}
+}
+
+// Update заменяет снимок, метрики которого отдаются
+func (e *Exporter) Update(s system.Snapshot) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.last = &s
+}
+
+// Run обновляет снимок из snapshots до закрытия канала
+func (e *Exporter) Run(snapshots <-chan system.Snapshot) {
+	for s := range snapshots {
+		e.Update(s)
+	}
+}
+
+// ServeHTTP отдает метрики в формате OpenMetrics, если клиент его принимает,
+// и в текстовом формате Prometheus в остальных случаях
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	last := e.last
+	e.mu.RUnlock()
+
+	switch {
+	case last == nil:
+		http.Error(w, "no data collected yet", http.StatusServiceUnavailable)
+		return
+	case last.Err != nil:
+		http.Error(w, last.Err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	format := FormatPrometheus
+	if strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text") {
+		format = FormatOpenMetrics
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	// Ошибка записи означает, что клиент отключился, - сообщить ее уже некому
+	_ = Write(w, *last, e.topN, format)
+}
+
+// ListenAndServe собирает снимки через collect каждые interval и отдает
+// их метрики по адресу addr на пути /metrics до отмены ctx
+func ListenAndServe(ctx context.Context, addr string, collect system.CollectFunc, interval time.Duration, topN int) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return serve(ctx, listener, collect, interval, topN)
+}
+
+// serve работает как ListenAndServe с уже открытым listener
+func serve(ctx context.Context, listener net.Listener, collect system.CollectFunc, interval time.Duration, topN int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	exporter := NewExporter(topN)
+	go exporter.Run(system.StartSampler(ctx, interval, collect))
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Serve(listener) }()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+func TestExporter_ServeHTTP(t *testing.T) {
+	exporter := NewExporter(5)
+
+	rec := httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 before the first snapshot, got %d", rec.Code)
+	}
+
+	exporter.Update(testSnapshot())
+	rec = httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != FormatPrometheus.ContentType() {
+		t.Errorf("Unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), "htop_processes 3\n") {
+		t.Errorf("Unexpected body:\n%s", rec.Body.String())
+	}
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5")
+	rec = httptest.NewRecorder()
+	exporter.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Type") != FormatOpenMetrics.ContentType() ||
+		!strings.HasSuffix(rec.Body.String(), "# EOF\n") {
+		t.Errorf("Expected OpenMetrics response, got %q:\n%s", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+
+	exporter.Update(system.Snapshot{Err: errors.New("failed to get memory info")})
+	rec = httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "memory") {
+		t.Errorf("Expected collection error, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestServe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collected := make(chan struct{}, 1)
+	collect := func(ctx context.Context) system.Snapshot {
+		select {
+		case collected <- struct{}{}:
+		default:
+		}
+		return testSnapshot()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- serve(ctx, listener, collect, time.Hour, 1) }()
+	<-collected
+
+	url := "http://" + listener.Addr().String() + "/metrics"
+	var body string
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatalf("GET %s: %v", url, err)
+		}
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			body = string(data)
+			break
+		}
+	}
+	if !strings.Contains(body, "htop_cpu_usage_ratio{cpu=\"1\"} 1\n") {
+		t.Errorf("Unexpected metrics:\n%s", body)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serve() вернул ошибку: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("serve() did not stop after cancel")
+	}
+}
+
+func TestListenAndServe_BadAddress(t *testing.T) {
+	err := ListenAndServe(context.Background(), "not-an-address", nil, time.Second, 0)
+	if err == nil {
+		t.Error("Expected error for invalid address")
+	}
+}
@@ -0,0 +1,174 @@
+package record
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// Скорости воспроизведения
+const (
+	minSpeed = 0.125
+	maxSpeed = 64
+)
+
+// errReplay - ошибка операций, недоступных при воспроизведении
+var errReplay = errors.New("not available in replay mode")
+
+// Player воспроизводит запись: хранит текущий кадр, паузу и скорость.
+// Player также реализует system.Source, отдавая данные текущего кадра,
+// поэтому интерфейс отображает запись тем же кодом, что и живые данные.
+// Player не потокобезопасен: им управляет цикл интерфейса.
+type Player struct {
+	frames []Frame
+	pos    int
+	paused bool
+	speed  float64
+}
+
+// NewPlayer создает Player для непустого набора кадров
+func NewPlayer(frames []Frame) *Player {
+	return &Player{frames: frames, speed: 1}
+}
+
+// OpenPlayer читает запись из файла и создает для нее Player
+func OpenPlayer(path string) (*Player, error) {
+	frames, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewPlayer(frames), nil
+}
+
+// Current возвращает текущий кадр
+func (p *Player) Current() Frame {
+	return p.frames[p.pos]
+}
+
+// Position возвращает номер текущего кадра (с нуля) и количество кадров
+func (p *Player) Position() (int, int) {
+	return p.pos, len(p.frames)
+}
+
+// Paused сообщает, остановлено ли воспроизведение
+func (p *Player) Paused() bool {
+	return p.paused
+}
+
+// Speed возвращает множитель скорости воспроизведения
+func (p *Player) Speed() float64 {
+	return p.speed
+}
+
+// TogglePause ставит воспроизведение на паузу или продолжает его.
+// На последнем кадре продолжение начинает запись сначала.
+func (p *Player) TogglePause() {
+	if p.paused && p.pos == len(p.frames)-1 {
+		p.pos = 0
+	}
+	p.paused = !p.paused
+}
+
+// Delay возвращает, через сколько показать следующий кадр с учетом скорости.
+// ok == false, если воспроизведение остановлено или запись закончилась.
+func (p *Player) Delay() (time.Duration, bool) {
+	if p.paused || p.pos >= len(p.frames)-1 {
+		return 0, false
+	}
+	gap := p.frames[p.pos+1].Time.Sub(p.frames[p.pos].Time)
+	return time.Duration(float64(gap) / p.speed), true
+}
+
+// Advance переходит к следующему кадру. В конце записи воспроизведение
+// останавливается.
+func (p *Player) Advance() {
+	if p.pos < len(p.frames)-1 {
+		p.pos++
+	}
+	if p.pos == len(p.frames)-1 {
+		p.paused = true
+	}
+}
+
+// Step перемещается на n кадров и ставит воспроизведение на паузу
+func (p *Player) Step(n int) {
+	p.paused = true
+	p.setPos(p.pos + n)
+}
+
+// Seek перемещается к кадру, ближайшему ко времени текущего кадра плюс offset
+func (p *Player) Seek(offset time.Duration) {
+	target := p.Current().Time.Add(offset)
+	i := sort.Search(len(p.frames), func(i int) bool { return !p.frames[i].Time.Before(target) })
+	if offset < 0 && i < len(p.frames) && p.frames[i].Time.After(target) && i > 0 {
+		i-- // назад - к кадру не позже целевого времени
+	}
+	p.setPos(i)
+}
+
+// SeekStart перемещается к первому кадру
+func (p *Player) SeekStart() {
+	p.setPos(0)
+}
+
+// SeekEnd перемещается к последнему кадру
+func (p *Player) SeekEnd() {
+	p.setPos(len(p.frames) - 1)
+}
+
+// Faster увеличивает скорость вдвое
+func (p *Player) Faster() {
+	if p.speed < maxSpeed {
+		p.speed *= 2
+	}
+}
+
+// Slower уменьшает скорость вдвое
+func (p *Player) Slower() {
+	if p.speed > minSpeed {
+		p.speed /= 2
+	}
+}
+
+// setPos устанавливает текущий кадр в пределах записи
+func (p *Player) setPos(pos int) {
+	if pos < 0 {
+		pos = 0
+	}
+	if pos > len(p.frames)-1 {
+		pos = len(p.frames) - 1
+	}
+	p.pos = pos
+}
+
+// CPUCount возвращает количество ядер в записи
+func (p *Player) CPUCount() (int, error) {
+	return len(p.frames[0].CPU), nil
+}
+
+// CPUPercent возвращает загрузку ядер текущего кадра
+func (p *Player) CPUPercent(ctx context.Context) ([]float64, error) {
+	return p.Current().CPU, nil
+}
+
+// Memory возвращает данные о памяти текущего кадра
+func (p *Player) Memory(ctx context.Context) (*mem.VirtualMemoryStat, error) {
+	memory := p.Current().Memory
+	return &memory, nil
+}
+
+// Processes возвращает процессы текущего кадра
+func (p *Player) Processes(ctx context.Context) ([]system.ProcessInfo, error) {
+	return p.Current().Processes, nil
+}
+
+// SendSignal недоступен: процессы записи уже не существуют
+func (p *Player) SendSignal(pid int32, start time.Time, sig syscall.Signal) error {
+	return errReplay
+}
@@ -0,0 +1,112 @@
+package record
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestPlayer_PlaysToEnd(t *testing.T) {
+	p := NewPlayer(testFrames(3))
+	if delay, ok := p.Delay(); !ok || delay != time.Second {
+		t.Errorf("Expected 1s until next frame, got %v, %v", delay, ok)
+	}
+
+	p.Faster()
+	if delay, _ := p.Delay(); delay != 500*time.Millisecond {
+		t.Errorf("Expected 500ms at x2, got %v", delay)
+	}
+
+	p.Advance()
+	p.Advance()
+	if pos, total := p.Position(); pos != 2 || total != 3 {
+		t.Errorf("Expected last frame, got %d/%d", pos, total)
+	}
+	// На последнем кадре воспроизведение останавливается
+	if _, ok := p.Delay(); ok || !p.Paused() {
+		t.Error("Expected playback to stop at the end")
+	}
+	// Продолжение с конца начинает запись заново
+	p.TogglePause()
+	if pos, _ := p.Position(); pos != 0 || p.Paused() {
+		t.Errorf("Expected restart from the first frame, got %d paused=%v", pos, p.Paused())
+	}
+}
+
+func TestPlayer_StepAndSeek(t *testing.T) {
+	p := NewPlayer(testFrames(200)) // 200 кадров с интервалом в секунду
+
+	p.Step(1)
+	if pos, _ := p.Position(); pos != 1 || !p.Paused() {
+		t.Errorf("Expected paused on frame 1, got %d paused=%v", pos, p.Paused())
+	}
+	p.Step(-5)
+	if pos, _ := p.Position(); pos != 0 {
+		t.Errorf("Expected step to stop at the first frame, got %d", pos)
+	}
+
+	p.Seek(time.Minute)
+	if pos, _ := p.Position(); pos != 60 {
+		t.Errorf("Expected frame 60 after seeking 1m, got %d", pos)
+	}
+	p.Seek(-90 * time.Second)
+	if pos, _ := p.Position(); pos != 0 {
+		t.Errorf("Expected frame 0 after seeking back past start, got %d", pos)
+	}
+	p.Seek(1500 * time.Millisecond)
+	if pos, _ := p.Position(); pos != 2 {
+		t.Errorf("Expected first frame at or after target, got %d", pos)
+	}
+	p.Seek(-500 * time.Millisecond)
+	if pos, _ := p.Position(); pos != 1 {
+		t.Errorf("Expected last frame at or before target, got %d", pos)
+	}
+
+	p.SeekEnd()
+	if pos, _ := p.Position(); pos != 199 {
+		t.Errorf("Expected last frame, got %d", pos)
+	}
+	p.SeekStart()
+	if pos, _ := p.Position(); pos != 0 {
+		t.Errorf("Expected first frame, got %d", pos)
+	}
+}
+
+func TestPlayer_SpeedLimits(t *testing.T) {
+	p := NewPlayer(testFrames(2))
+	for i := 0; i < 20; i++ {
+		p.Faster()
+	}
+	if p.Speed() != maxSpeed {
+		t.Errorf("Expected speed capped at %v, got %v", float64(maxSpeed), p.Speed())
+	}
+	for i := 0; i < 20; i++ {
+		p.Slower()
+	}
+	if p.Speed() != minSpeed {
+		t.Errorf("Expected speed floored at %v, got %v", minSpeed, p.Speed())
+	}
+}
+
+func TestPlayer_Source(t *testing.T) {
+	p := NewPlayer(testFrames(3))
+	p.Step(2)
+	ctx := context.Background()
+
+	if count, _ := p.CPUCount(); count != 2 {
+		t.Errorf("Expected 2 recorded cores, got %d", count)
+	}
+	if cpu, _ := p.CPUPercent(ctx); cpu[0] != 2 {
+		t.Errorf("Expected CPU of the current frame, got %v", cpu)
+	}
+	if memory, _ := p.Memory(ctx); memory.Used != 2 {
+		t.Errorf("Expected memory of the current frame, got %+v", memory)
+	}
+	if processes, _ := p.Processes(ctx); processes[1].PID != 102 {
+		t.Errorf("Expected processes of the current frame, got %+v", processes)
+	}
+	if err := p.SendSignal(1, time.Time{}, syscall.SIGTERM); err == nil {
+		t.Error("Expected signals to be unavailable in replay")
+	}
+}
@@ -0,0 +1,144 @@
+package record
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// magic - заголовок файла записи. Последний байт - версия формата.
+var magic = []byte("HTOPREC\x01")
+
+// Frame - один записанный снимок: все собранные метрики на момент Time
+type Frame struct {
+	Time       time.Time
+	CPU        []float64
+	Memory     mem.VirtualMemoryStat
+	Processes  []system.ProcessInfo
+	ProcessErr string // ошибка получения списка процессов, если была
+}
+
+// FrameFromSnapshot создает кадр из снимка состояния системы
+func FrameFromSnapshot(s system.Snapshot) Frame {
+	f := Frame{Time: s.Time, CPU: s.CPU, Processes: s.Processes}
+	if s.Memory != nil {
+		f.Memory = *s.Memory
+	}
+	if s.ProcessErr != nil {
+		f.ProcessErr = s.ProcessErr.Error()
+	}
+	return f
+}
+
+// Snapshot возвращает снимок, который интерфейс отображает так же, как живые данные
+func (f Frame) Snapshot() system.Snapshot {
+	memory := f.Memory
+	s := system.Snapshot{Time: f.Time, CPU: f.CPU, Memory: &memory, Processes: f.Processes}
+	if f.ProcessErr != "" {
+		s.ProcessErr = errors.New(f.ProcessErr)
+	}
+	return s
+}
+
+// Writer записывает кадры в файл: заголовок и поток gob, сжатый gzip.
+// Каждый кадр сбрасывается на диск сразу, поэтому при аварийном завершении
+// сохраняются все кадры, кроме, возможно, последнего.
+type Writer struct {
+	file    *os.File
+	gzip    *gzip.Writer
+	encoder *gob.Encoder
+}
+
+// Create создает файл записи, заменяя существующий
+func Create(path string) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %v", err)
+	}
+	if _, err := file.Write(magic); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write recording: %v", err)
+	}
+	zw := gzip.NewWriter(file)
+	return &Writer{file: file, gzip: zw, encoder: gob.NewEncoder(zw)}, nil
+}
+
+// Write добавляет кадр в запись
+func (w *Writer) Write(f Frame) error {
+	if err := w.encoder.Encode(f); err != nil {
+		return fmt.Errorf("failed to write recording: %v", err)
+	}
+	if err := w.gzip.Flush(); err != nil {
+		return fmt.Errorf("failed to write recording: %v", err)
+	}
+	return nil
+}
+
+// Close завершает запись и закрывает файл
+func (w *Writer) Close() error {
+	err := w.gzip.Close()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to close recording: %v", err)
+	}
+	return nil
+}
+
+// ReadFile читает все кадры записи. Оборванный последний кадр (запись была
+// прервана) отбрасывается без ошибки.
+func ReadFile(path string) ([]Frame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %v", err)
+	}
+	defer file.Close()
+	return Read(file)
+}
+
+// Read читает все кадры записи из r
+func Read(r io.Reader) ([]Frame, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:len(header)-1]) != string(magic[:len(magic)-1]) {
+		return nil, errors.New("not an htop recording")
+	}
+	if header[len(header)-1] != magic[len(magic)-1] {
+		return nil, fmt.Errorf("unsupported recording version %d", header[len(header)-1])
+	}
+
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("corrupted recording: %v", err)
+	}
+	decoder := gob.NewDecoder(zr)
+	var frames []Frame
+	for {
+		var f Frame
+		err := decoder.Decode(&f)
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			if len(frames) > 0 {
+				break // поврежденный хвост прерванной записи
+			}
+			return nil, fmt.Errorf("corrupted recording: %v", err)
+		}
+		frames = append(frames, f)
+	}
+	if len(frames) == 0 {
+		return nil, errors.New("recording has no frames")
+	}
+	return frames, nil
+}
@@ -0,0 +1,120 @@
+package record
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// testFrames возвращает n кадров с интервалом в секунду
+func testFrames(n int) []Frame {
+	start := time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC)
+	frames := make([]Frame, n)
+	for i := range frames {
+		frames[i] = Frame{
+			Time:   start.Add(time.Duration(i) * time.Second),
+			CPU:    []float64{float64(i), 50},
+			Memory: mem.VirtualMemoryStat{Total: 1000, Used: uint64(i), UsedPercent: float64(i) / 10},
+			Processes: []system.ProcessInfo{
+				{PID: 1, Name: "init", StartTime: start.Add(-time.Hour), CPUTime: time.Second},
+				{PID: int32(100 + i), Name: "job", Cmdline: "job --n", CPU: 12.5},
+			},
+		}
+	}
+	return frames
+}
+
+func writeFrames(t *testing.T, frames []Frame) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.rec")
+	w, err := Create(path)
+	if err != nil {
+		t.Fatalf("Create() вернул ошибку: %v", err)
+	}
+	for _, f := range frames {
+		if err := w.Write(f); err != nil {
+			t.Fatalf("Write() вернул ошибку: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() вернул ошибку: %v", err)
+	}
+	return path
+}
+
+func TestWriteAndRead(t *testing.T) {
+	frames := testFrames(3)
+	got, err := ReadFile(writeFrames(t, frames))
+	if err != nil {
+		t.Fatalf("ReadFile() вернул ошибку: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 frames, got %d", len(got))
+	}
+	for i := range frames {
+		if !got[i].Time.Equal(frames[i].Time) || got[i].Memory != frames[i].Memory ||
+			got[i].Processes[1] != frames[i].Processes[1] || got[i].CPU[0] != frames[i].CPU[0] {
+			t.Errorf("Frame %d differs:\n got %+v\nwant %+v", i, got[i], frames[i])
+		}
+		if !got[i].Processes[0].StartTime.Equal(frames[i].Processes[0].StartTime) {
+			t.Errorf("Frame %d: start time differs", i)
+		}
+	}
+}
+
+func TestRead_TruncatedRecording(t *testing.T) {
+	path := writeFrames(t, testFrames(5))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Запись прервана посреди последнего кадра
+	frames, err := Read(bytes.NewReader(data[:len(data)-40]))
+	if err != nil {
+		t.Fatalf("Read() вернул ошибку: %v", err)
+	}
+	if len(frames) == 0 || len(frames) > 5 {
+		t.Errorf("Expected the complete frames to be kept, got %d", len(frames))
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"Not a recording", []byte("{\"json\": true}"), "not an htop recording"},
+		{"Future version", []byte("HTOPREC\x07"), "unsupported recording version 7"},
+		{"No frames", []byte("HTOPREC\x01"), "corrupted recording"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(bytes.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFrameSnapshot(t *testing.T) {
+	s := system.Snapshot{
+		Time:       time.Now(),
+		CPU:        []float64{1},
+		Memory:     &mem.VirtualMemoryStat{Total: 10},
+		ProcessErr: errors.New("permission denied"),
+	}
+	back := FrameFromSnapshot(s).Snapshot()
+	if back.Memory.Total != 10 || back.ProcessErr == nil || back.ProcessErr.Error() != "permission denied" {
+		t.Errorf("Unexpected round trip: %+v", back)
+	}
+}
@@ -0,0 +1,100 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"syscall"
+)
+
+// AffinitySource - Source, умеющий читать и менять привязку процессов к ядрам.
+// Источники без доступа к процессам (запись сеанса) этот интерфейс не реализуют.
+type AffinitySource interface {
+	// Affinity возвращает номера ядер, на которых может выполняться процесс
+	Affinity(pid int32) ([]int, error)
+	// SetAffinity привязывает процесс к ядрам cpus. Если allThreads == true,
+	// привязка меняется у всех потоков процесса, иначе только у основного.
+	SetAffinity(pid int32, cpus []int, allThreads bool) error
+}
+
+// Affinity возвращает номера ядер, на которых может выполняться основной
+// поток процесса, по возрастанию
+func Affinity(pid int32) ([]int, error) {
+	cpus, err := getAffinity(pid)
+	if err != nil {
+		return nil, affinityError("read CPU affinity", pid, err)
+	}
+	return cpus, nil
+}
+
+// SetAffinity привязывает процесс к ядрам cpus. Привязка в Linux задается
+// каждому потоку отдельно: при allThreads == false она меняется только у
+// основного потока, а потоки, созданные им позже, наследуют ее.
+func SetAffinity(pid int32, cpus []int, allThreads bool) error {
+	if len(cpus) == 0 {
+		return errors.New("invalid CPU affinity: at least one CPU must be selected")
+	}
+	for _, cpu := range cpus {
+		if cpu < 0 || cpu >= maxAffinityCPUs {
+			return fmt.Errorf("invalid CPU affinity: no CPU %d", cpu)
+		}
+	}
+	action := fmt.Sprintf("set CPU affinity %s", FormatCPUList(cpus))
+	if !allThreads {
+		if err := setAffinity(pid, cpus); err != nil {
+			return affinityError(action, pid, err)
+		}
+		return nil
+	}
+
+	tids, err := threadIDs(pid)
+	if err != nil {
+		return affinityError(action, pid, err)
+	}
+	var errs []error
+	for _, tid := range tids {
+		err := setAffinity(tid, cpus)
+		// Поток мог завершиться после чтения списка - это не ошибка
+		if err != nil && !(tid != pid && errors.Is(err, syscall.ESRCH)) {
+			errs = append(errs, affinityError(action, tid, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// affinityError поясняет ошибку чтения или изменения привязки к ядрам
+func affinityError(action string, pid int32, err error) error {
+	return actionError(action, pid, err, "changing processes of other users requires root or CAP_SYS_NICE")
+}
+
+// FormatCPUList записывает номера ядер диапазонами, как в taskset и cpuset: 0-3,6,8-9
+func FormatCPUList(cpus []int) string {
+	sorted := append([]int(nil), cpus...)
+	sort.Ints(sorted)
+	var list []byte
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] <= sorted[j]+1 {
+			j++
+		}
+		if len(list) > 0 {
+			list = append(list, ',')
+		}
+		list = fmt.Appendf(list, "%d", sorted[i])
+		if sorted[j] != sorted[i] {
+			list = fmt.Appendf(list, "-%d", sorted[j])
+		}
+		i = j + 1
+	}
+	return string(list)
+}
+
+// Affinity возвращает привязку к ядрам процесса текущей машины
+func (s *LiveSource) Affinity(pid int32) ([]int, error) {
+	return Affinity(pid)
+}
+
+// SetAffinity привязывает процесс текущей машины к ядрам
+func (s *LiveSource) SetAffinity(pid int32, cpus []int, allThreads bool) error {
+	return SetAffinity(pid, cpus, allThreads)
+}
@@ -0,0 +1,90 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/bits"
+	"os"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// maxAffinityCPUs - наибольшее количество ядер, которое поддерживает ядро Linux
+// (CONFIG_NR_CPUS). Маска такого размера подходит любому ядру.
+const maxAffinityCPUs = 8192
+
+// cpuMask - битовая маска ядер для sched_getaffinity и sched_setaffinity
+type cpuMask [maxAffinityCPUs / 64]uint64
+
+// getAffinity читает привязку потока к ядрам
+func getAffinity(pid int32) ([]int, error) {
+	var mask cpuMask
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY,
+		uintptr(pid), unsafe.Sizeof(mask), uintptr(unsafe.Pointer(&mask)))
+	if errno != 0 {
+		return nil, errno
+	}
+	return mask.cpus(), nil
+}
+
+// setAffinity задает привязку потока к ядрам
+func setAffinity(pid int32, cpus []int) error {
+	mask := newCPUMask(cpus)
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY,
+		uintptr(pid), unsafe.Sizeof(mask), uintptr(unsafe.Pointer(&mask)))
+	if errno == syscall.EINVAL {
+		// Ядро отклоняет маску, в которой нет ни одного доступного ядра
+		return fmt.Errorf("none of CPUs %s is online or allowed by the cpuset: %w", FormatCPUList(cpus), errno)
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// newCPUMask строит маску из номеров ядер
+func newCPUMask(cpus []int) cpuMask {
+	var mask cpuMask
+	for _, cpu := range cpus {
+		mask[cpu/64] |= 1 << (cpu % 64)
+	}
+	return mask
+}
+
+// cpus возвращает номера ядер маски по возрастанию
+func (m *cpuMask) cpus() []int {
+	var cpus []int
+	for i, word := range m {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			cpus = append(cpus, i*64+bit)
+			word &^= 1 << bit
+		}
+	}
+	return cpus
+}
+
+// threadIDs возвращает идентификаторы потоков процесса из /proc/[pid]/task
+func threadIDs(pid int32) ([]int32, error) {
+	dir, err := os.Open(fmt.Sprintf("/proc/%d/task", pid))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, syscall.ESRCH
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+	tids := make([]int32, 0, len(names))
+	for _, name := range names {
+		if tid, err := strconv.ParseInt(name, 10, 32); err == nil {
+			tids = append(tids, int32(tid))
+		}
+	}
+	return tids, nil
+}
@@ -0,0 +1,48 @@
+package system
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+func TestCPUMask(t *testing.T) {
+	cpus := []int{0, 5, 63, 64, 1000}
+	mask := newCPUMask(cpus)
+	if got := mask.cpus(); !slices.Equal(got, cpus) {
+		t.Errorf("cpuMask round trip = %v, want %v", got, cpus)
+	}
+}
+
+func TestAffinity_Self(t *testing.T) {
+	pid := int32(os.Getpid())
+	cpus, err := Affinity(pid)
+	if err != nil {
+		t.Fatalf("Affinity() вернула ошибку: %v", err)
+	}
+	if len(cpus) == 0 || len(cpus) > runtime.NumCPU() {
+		t.Fatalf("Некорректная привязка к ядрам: %v", cpus)
+	}
+
+	// Повторная установка текущей привязки ничего не меняет и не требует прав
+	if err := SetAffinity(pid, cpus, true); err != nil {
+		t.Fatalf("SetAffinity() to the current CPUs failed: %v", err)
+	}
+	after, err := Affinity(pid)
+	if err != nil || !slices.Equal(after, cpus) {
+		t.Errorf("Expected affinity %v to be kept, got %v (%v)", cpus, after, err)
+	}
+}
+
+func TestAffinity_MissingProcess(t *testing.T) {
+	// PID вне диапазона ядра не может принадлежать процессу
+	if _, err := Affinity(1 << 30); !errors.Is(err, syscall.ESRCH) {
+		t.Errorf("Expected ESRCH for a missing process, got %v", err)
+	}
+	if err := SetAffinity(1<<30, []int{0}, true); !errors.Is(err, syscall.ESRCH) {
+		t.Errorf("Expected ESRCH for a missing process, got %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !linux
+// +build !linux
+
+package system
+
+import "errors"
+
+// maxAffinityCPUs - на этих платформах привязка к ядрам не поддерживается
+const maxAffinityCPUs = 0
+
+// errNoAffinity - привязка к ядрам есть только в Linux
+var errNoAffinity = errors.New("CPU affinity is only supported on Linux")
+
+// getAffinity на этих платформах не поддерживается
+func getAffinity(pid int32) ([]int, error) {
+	return nil, errNoAffinity
+}
+
+// setAffinity на этих платформах не поддерживается
+func setAffinity(pid int32, cpus []int) error {
+	return errNoAffinity
+}
+
+// threadIDs на этих платформах не поддерживается
+func threadIDs(pid int32) ([]int32, error) {
+	return nil, errNoAffinity
+}
@@ -0,0 +1,30 @@
+package system
+
+import "testing"
+
+func TestFormatCPUList(t *testing.T) {
+	tests := []struct {
+		cpus []int
+		want string
+	}{
+		{nil, ""},
+		{[]int{3}, "3"},
+		{[]int{0, 1, 2, 3}, "0-3"},
+		{[]int{9, 0, 2, 8, 1, 6}, "0-2,6,8-9"},
+		{[]int{1, 1, 2}, "1-2"},
+	}
+	for _, tt := range tests {
+		if got := FormatCPUList(tt.cpus); got != tt.want {
+			t.Errorf("FormatCPUList(%v) = %q, want %q", tt.cpus, got, tt.want)
+		}
+	}
+}
+
+func TestSetAffinity_Invalid(t *testing.T) {
+	if err := SetAffinity(1, nil, false); err == nil {
+		t.Error("Expected error for an empty CPU set")
+	}
+	if err := SetAffinity(1, []int{-1}, false); err == nil {
+		t.Error("Expected error for a negative CPU")
+	}
+}
@@ -0,0 +1,43 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readCgroup возвращает путь cgroup процесса из /proc/[pid]/cgroup
+func readCgroup(pid int32) (string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+	return parseCgroup(string(data)), nil
+}
+
+// parseCgroup выбирает путь из содержимого /proc/[pid]/cgroup. Для cgroup v2
+// используется единая иерархия "0::", для v1 - иерархия systemd или первая
+// непустая, так как именно они отражают принадлежность к сервису.
+func parseCgroup(data string) string {
+	var first, systemd string
+	for _, line := range strings.Split(data, "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		id, controllers, path := parts[0], parts[1], parts[2]
+		if id == "0" && controllers == "" {
+			return path
+		}
+		if controllers == "name=systemd" {
+			systemd = path
+		}
+		if first == "" && path != "/" {
+			first = path
+		}
+	}
+	if systemd != "" {
+		return systemd
+	}
+	return first
+}
@@ -0,0 +1,41 @@
+package system
+
+import "testing"
+
+func TestParseCgroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{
+			name:     "cgroup v2",
+			data:     "0::/system.slice/nginx.service\n",
+			expected: "/system.slice/nginx.service",
+		},
+		{
+			name: "cgroup v1 prefers systemd hierarchy",
+			data: "12:cpu,cpuacct:/docker/abc\n" +
+				"1:name=systemd:/system.slice/docker.service\n",
+			expected: "/system.slice/docker.service",
+		},
+		{
+			name:     "cgroup v1 without systemd",
+			data:     "5:memory:/\n4:cpu:/batch/job1\n",
+			expected: "/batch/job1",
+		},
+		{
+			name:     "Empty",
+			data:     "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCgroup(tt.data); got != tt.expected {
+				t.Errorf("parseCgroup() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package system
+
+// readCgroup возвращает пустую строку: cgroup есть только в Linux
+func readCgroup(pid int32) (string, error) {
+	return "", nil
+}
@@ -0,0 +1,123 @@
+package system
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// CPUMode определяет, относительно чего нормируется загрузка CPU процесса
+type CPUMode int
+
+const (
+	// CPUPerCore - 100% соответствует одному полностью занятому ядру (как в htop),
+	// многопоточный процесс может показывать больше 100%
+	CPUPerCore CPUMode = iota
+	// CPUTotal - 100% соответствует всем ядрам машины
+	CPUTotal
+)
+
+// CPUTime содержит накопленное процессорное время процесса на момент замера
+type CPUTime struct {
+	PID        int32
+	CreateTime int64   // время запуска процесса в миллисекундах, отличает процессы с одинаковым PID
+	Seconds    float64 // user + system время в секундах
+}
+
+// cpuSample - предыдущий замер процесса
+type cpuSample struct {
+	createTime int64
+	seconds    float64
+	at         time.Time
+}
+
+// CPUSampler хранит процессорное время каждого процесса между тиками и
+// вычисляет загрузку за фактический интервал между замерами, а не среднее
+// значение за все время жизни процесса
+type CPUSampler struct {
+	mu      sync.Mutex
+	mode    CPUMode
+	numCPU  int
+	samples map[int32]cpuSample
+}
+
+// NewCPUSampler создает CPUSampler с указанным режимом нормировки
+func NewCPUSampler(mode CPUMode) *CPUSampler {
+	numCPU, err := cpu.Counts(true)
+	if err != nil || numCPU <= 0 {
+		numCPU = runtime.NumCPU()
+	}
+	return &CPUSampler{
+		mode:    mode,
+		numCPU:  numCPU,
+		samples: make(map[int32]cpuSample),
+	}
+}
+
+// SetMode меняет режим нормировки загрузки
+func (s *CPUSampler) SetMode(mode CPUMode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mode = mode
+}
+
+// Mode возвращает текущий режим нормировки загрузки
+func (s *CPUSampler) Mode() CPUMode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mode
+}
+
+// Sample принимает замеры процессорного времени всех процессов на момент at
+// и возвращает загрузку CPU в процентах по PID. Для процесса без предыдущего
+// замера (первый тик или новый процесс) используется среднее за время жизни.
+// Замеры завершившихся процессов отбрасываются.
+func (s *CPUSampler) Sample(at time.Time, times []CPUTime) map[int32]float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[int32]float64, len(times))
+	samples := make(map[int32]cpuSample, len(times))
+	for _, t := range times {
+		var delta float64
+		var elapsed time.Duration
+
+		prev, ok := s.samples[t.PID]
+		if ok && prev.createTime == t.CreateTime {
+			delta = t.Seconds - prev.seconds
+			elapsed = at.Sub(prev.at)
+		} else if t.CreateTime > 0 {
+			// PID новый или переиспользован - считаем от момента запуска
+			delta = t.Seconds
+			elapsed = at.Sub(time.UnixMilli(t.CreateTime))
+		}
+
+		percent := 0.0
+		if elapsed > 0 && delta > 0 {
+			percent = delta / elapsed.Seconds() * 100
+			if s.mode == CPUTotal && s.numCPU > 0 {
+				percent /= float64(s.numCPU)
+			}
+			// На коротких интервалах дискретность счетчиков ядра (тики) дает
+			// значения выше физически возможных
+			if limit := s.maxPercent(); percent > limit {
+				percent = limit
+			}
+		}
+		result[t.PID] = percent
+		samples[t.PID] = cpuSample{createTime: t.CreateTime, seconds: t.Seconds, at: at}
+	}
+	s.samples = samples
+
+	return result
+}
+
+// maxPercent возвращает максимально возможную загрузку в текущем режиме
+func (s *CPUSampler) maxPercent() float64 {
+	if s.mode == CPUTotal || s.numCPU <= 0 {
+		return 100
+	}
+	return 100 * float64(s.numCPU)
+}
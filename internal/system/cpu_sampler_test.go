@@ -0,0 +1,123 @@
+package system
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func newTestSampler(mode CPUMode, numCPU int) *CPUSampler {
+	s := NewCPUSampler(mode)
+	s.numCPU = numCPU
+	return s
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestCPUSampler_Interval(t *testing.T) {
+	start := time.UnixMilli(1_000_000)
+	created := start.Add(-100 * time.Second).UnixMilli()
+	s := newTestSampler(CPUPerCore, 4)
+
+	// Первый замер: процесс 100 секунд простаивал, затем начал работать.
+	// Без предыдущего замера берем среднее за время жизни: 1с / 100с = 1%
+	first := s.Sample(start, []CPUTime{{PID: 1, CreateTime: created, Seconds: 1}})
+	if !almostEqual(first[1], 1) {
+		t.Errorf("Expected lifetime average 1%%, got %f", first[1])
+	}
+
+	// За следующую секунду процесс полностью занял одно ядро
+	second := s.Sample(start.Add(time.Second), []CPUTime{{PID: 1, CreateTime: created, Seconds: 2}})
+	if !almostEqual(second[1], 100) {
+		t.Errorf("Expected 100%% over the interval, got %f", second[1])
+	}
+
+	// За две секунды процесс использовал 3 секунды CPU на нескольких ядрах
+	third := s.Sample(start.Add(3*time.Second), []CPUTime{{PID: 1, CreateTime: created, Seconds: 5}})
+	if !almostEqual(third[1], 150) {
+		t.Errorf("Expected 150%% for multi-threaded process, got %f", third[1])
+	}
+}
+
+func TestCPUSampler_TotalMode(t *testing.T) {
+	start := time.UnixMilli(1_000_000)
+	created := start.Add(-time.Hour).UnixMilli()
+	s := newTestSampler(CPUTotal, 4)
+
+	s.Sample(start, []CPUTime{{PID: 1, CreateTime: created, Seconds: 10}})
+	got := s.Sample(start.Add(time.Second), []CPUTime{{PID: 1, CreateTime: created, Seconds: 11}})
+	if !almostEqual(got[1], 25) {
+		t.Errorf("Expected one busy core of four to be 25%%, got %f", got[1])
+	}
+
+	s.SetMode(CPUPerCore)
+	if s.Mode() != CPUPerCore {
+		t.Errorf("Expected mode to change to CPUPerCore, got %v", s.Mode())
+	}
+}
+
+func TestCPUSampler_PIDReuse(t *testing.T) {
+	start := time.UnixMilli(1_000_000)
+	s := newTestSampler(CPUPerCore, 1)
+
+	s.Sample(start, []CPUTime{{PID: 42, CreateTime: start.Add(-time.Hour).UnixMilli(), Seconds: 500}})
+
+	// Под тем же PID запустился новый процесс: разница с чужим замером была бы отрицательной
+	created := start.Add(500 * time.Millisecond).UnixMilli()
+	got := s.Sample(start.Add(time.Second), []CPUTime{{PID: 42, CreateTime: created, Seconds: 0.25}})
+	if !almostEqual(got[42], 50) {
+		t.Errorf("Expected reused PID to be measured from its start time (50%%), got %f", got[42])
+	}
+}
+
+func TestCPUSampler_DropsExitedProcesses(t *testing.T) {
+	start := time.UnixMilli(1_000_000)
+	created := start.Add(-time.Hour).UnixMilli()
+	s := newTestSampler(CPUPerCore, 1)
+
+	s.Sample(start, []CPUTime{
+		{PID: 1, CreateTime: created, Seconds: 1},
+		{PID: 2, CreateTime: created, Seconds: 1},
+	})
+	s.Sample(start.Add(time.Second), []CPUTime{{PID: 1, CreateTime: created, Seconds: 1}})
+
+	if _, ok := s.samples[2]; ok {
+		t.Error("Expected sample of exited process to be dropped")
+	}
+	if len(s.samples) != 1 {
+		t.Errorf("Expected 1 stored sample, got %d", len(s.samples))
+	}
+}
+
+func TestGetProcessListWithSampler(t *testing.T) {
+	sampler := NewCPUSampler(CPUTotal)
+	if _, err := GetProcessListWithSampler(sampler); err != nil {
+		t.Fatalf("GetProcessListWithSampler() вернула ошибку: %v", err)
+	}
+
+	processes, err := GetProcessListWithSampler(sampler)
+	if err != nil {
+		t.Fatalf("GetProcessListWithSampler() вернула ошибку: %v", err)
+	}
+	for _, p := range processes {
+		// В режиме CPUTotal загрузка не может превышать 100%
+		if p.CPU < 0 || p.CPU > 100 {
+			t.Errorf("Процесс %s (PID: %d) имеет некорректное значение CPU: %f", p.Name, p.PID, p.CPU)
+		}
+	}
+}
+
+func TestCPUSampler_ClampsToPhysicalLimit(t *testing.T) {
+	start := time.UnixMilli(1_000_000)
+	created := start.Add(-time.Hour).UnixMilli()
+	s := newTestSampler(CPUPerCore, 2)
+
+	s.Sample(start, []CPUTime{{PID: 1, CreateTime: created, Seconds: 1}})
+	// 30 мс CPU за 10 мс на двух ядрах - артефакт дискретности счетчиков
+	got := s.Sample(start.Add(10*time.Millisecond), []CPUTime{{PID: 1, CreateTime: created, Seconds: 1.03}})
+	if !almostEqual(got[1], 200) {
+		t.Errorf("Expected usage to be clamped to 200%%, got %f", got[1])
+	}
+}
@@ -0,0 +1,49 @@
+package system
+
+import (
+	"context"
+)
+
+// ProcessDetail - подробные сведения о процессе для панели процесса.
+// Часть сведений о чужих процессах доступна только root: такие поля
+// остаются пустыми, а причина записывается в Errors.
+type ProcessDetail struct {
+	PID     int32
+	Args    []string // аргументы командной строки
+	Exe     string   // путь к исполняемому файлу
+	Cwd     string   // текущий каталог
+	UID     int32    // реальный UID владельца
+	EUID    int32    // эффективный UID
+	User    string   // имя владельца по реальному UID
+	Environ []string // переменные окружения в виде "KEY=value"
+	Limits  []Limit
+	Errors  map[string]error // ошибки чтения по названию поля: "exe", "cwd", "environ", "limits"
+}
+
+// Limit - ограничение ресурса процесса
+type Limit struct {
+	Name  string // название ресурса, например "Max open files"
+	Soft  string // мягкое ограничение или "unlimited"
+	Hard  string // жесткое ограничение или "unlimited"
+	Units string // единицы измерения, может быть пустой
+}
+
+// DetailSource - Source, умеющий читать подробные сведения о процессе.
+// Источники без доступа к процессам (запись сеанса) этот интерфейс не реализуют.
+type DetailSource interface {
+	// ProcessDetail возвращает сведения о процессе или ошибку, если процесса нет
+	ProcessDetail(ctx context.Context, pid int32) (*ProcessDetail, error)
+}
+
+// ProcessDetail возвращает подробные сведения о процессе текущей машины
+func (s *LiveSource) ProcessDetail(ctx context.Context, pid int32) (*ProcessDetail, error) {
+	return readProcessDetail(ctx, pid)
+}
+
+// setError запоминает ошибку чтения поля
+func (d *ProcessDetail) setError(field string, err error) {
+	if d.Errors == nil {
+		d.Errors = make(map[string]error)
+	}
+	d.Errors[field] = err
+}
@@ -0,0 +1,115 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readProcessDetail читает сведения о процессе текущей машины из /proc
+func readProcessDetail(ctx context.Context, pid int32) (*ProcessDetail, error) {
+	return readProcessDetailAt("/proc", pid)
+}
+
+// readProcessDetailAt читает сведения о процессе из procfs в каталоге root
+func readProcessDetailAt(root string, pid int32) (*ProcessDetail, error) {
+	dir := filepath.Join(root, strconv.Itoa(int(pid)))
+	status, err := os.ReadFile(filepath.Join(dir, "status"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read process %d: %v", pid, err)
+	}
+	d := &ProcessDetail{PID: pid}
+	d.UID, d.EUID = parseStatusUIDs(status)
+	d.User = lookupUser(d.UID)
+
+	// cmdline доступен всем, но пуст у потоков ядра и зомби
+	if data, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		d.Args = splitNul(data)
+	}
+	if d.Exe, err = os.Readlink(filepath.Join(dir, "exe")); err != nil {
+		d.setError("exe", err)
+	}
+	if d.Cwd, err = os.Readlink(filepath.Join(dir, "cwd")); err != nil {
+		d.setError("cwd", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "environ")); err != nil {
+		d.setError("environ", err)
+	} else {
+		d.Environ = splitNul(data)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "limits")); err != nil {
+		d.setError("limits", err)
+	} else if d.Limits, err = parseLimits(data); err != nil {
+		d.setError("limits", err)
+	}
+	return d, nil
+}
+
+// ProcessDetail возвращает сведения о процессе из procfs
+func (s *ProcSource) ProcessDetail(ctx context.Context, pid int32) (*ProcessDetail, error) {
+	return readProcessDetailAt(s.root, pid)
+}
+
+// parseStatusUIDs возвращает реальный и эффективный UID из строки "Uid:"
+// файла /proc/[pid]/status
+func parseStatusUIDs(data []byte) (uid, euid int32) {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if !bytes.HasPrefix(line, []byte("Uid:")) {
+			continue
+		}
+		fields := bytes.Fields(line[len("Uid:"):])
+		if len(fields) >= 2 {
+			real, _ := strconv.ParseInt(string(fields[0]), 10, 32)
+			effective, _ := strconv.ParseInt(string(fields[1]), 10, 32)
+			return int32(real), int32(effective)
+		}
+	}
+	return 0, 0
+}
+
+// splitNul разбивает строки, разделенные нулевыми байтами
+func splitNul(data []byte) []string {
+	var values []string
+	for _, field := range bytes.Split(bytes.TrimRight(data, "\x00"), []byte{0}) {
+		if len(field) > 0 {
+			values = append(values, string(field))
+		}
+	}
+	return values
+}
+
+// parseLimits разбирает таблицу /proc/[pid]/limits. Колонки выровнены
+// пробелами, а названия ресурсов сами содержат пробелы, поэтому значения
+// берутся по позициям заголовков.
+func parseLimits(data []byte) ([]Limit, error) {
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	header := lines[0]
+	soft, hard, units := strings.Index(header, "Soft Limit"), strings.Index(header, "Hard Limit"), strings.Index(header, "Units")
+	if soft < 0 || hard < soft || units < hard {
+		return nil, fmt.Errorf("malformed limits header %q", header)
+	}
+
+	column := func(line string, from, to int) string {
+		if from >= len(line) {
+			return ""
+		}
+		if to > len(line) || to < 0 {
+			to = len(line)
+		}
+		return strings.TrimSpace(line[from:to])
+	}
+	limits := make([]Limit, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		limits = append(limits, Limit{
+			Name:  column(line, 0, soft),
+			Soft:  column(line, soft, hard),
+			Hard:  column(line, hard, units),
+			Units: column(line, units, -1),
+		})
+	}
+	return limits, nil
+}
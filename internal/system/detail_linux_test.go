@@ -0,0 +1,81 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProcSource_ProcessDetail(t *testing.T) {
+	source, err := OpenProcSource(filepath.Join("testdata", "proc", "basic"), CPUPerCore)
+	if err != nil {
+		t.Fatalf("OpenProcSource() вернул ошибку: %v", err)
+	}
+	d, err := source.(DetailSource).ProcessDetail(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("ProcessDetail() вернул ошибку: %v", err)
+	}
+
+	if want := []string{"/usr/bin/web-server", "--port", "8080"}; !reflect.DeepEqual(d.Args, want) {
+		t.Errorf("Args = %q, want %q", d.Args, want)
+	}
+	if d.Exe != "/usr/bin/web-server" || d.Cwd != "/srv/web" {
+		t.Errorf("Unexpected exe %q and cwd %q", d.Exe, d.Cwd)
+	}
+	if d.UID != 4242 || d.EUID != 4242 || d.User == "" {
+		t.Errorf("Unexpected owner: uid %d euid %d user %q", d.UID, d.EUID, d.User)
+	}
+	if want := []string{"PATH=/usr/bin:/bin", "HOME=/srv/web", "LANG=C.UTF-8"}; !reflect.DeepEqual(d.Environ, want) {
+		t.Errorf("Environ = %q, want %q", d.Environ, want)
+	}
+	want := []Limit{
+		{Name: "Max cpu time", Soft: "unlimited", Hard: "unlimited", Units: "seconds"},
+		{Name: "Max open files", Soft: "1024", Hard: "524288", Units: "files"},
+		{Name: "Max nice priority", Soft: "0", Hard: "0"},
+	}
+	if !reflect.DeepEqual(d.Limits, want) {
+		t.Errorf("Limits = %+v, want %+v", d.Limits, want)
+	}
+	if len(d.Errors) != 0 {
+		t.Errorf("Unexpected errors: %v", d.Errors)
+	}
+}
+
+func TestProcSource_ProcessDetailRestricted(t *testing.T) {
+	source, err := OpenProcSource(filepath.Join("testdata", "proc", "basic"), CPUPerCore)
+	if err != nil {
+		t.Fatalf("OpenProcSource() вернул ошибку: %v", err)
+	}
+	// У потока ядра нет environ, limits и ссылок в дереве - как у чужого процесса без прав
+	d, err := source.(DetailSource).ProcessDetail(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("ProcessDetail() вернул ошибку: %v", err)
+	}
+	for _, field := range []string{"exe", "cwd", "environ", "limits"} {
+		if d.Errors[field] == nil {
+			t.Errorf("Expected %s error, got %v", field, d.Errors)
+		}
+	}
+	if len(d.Args) != 0 {
+		t.Errorf("Expected no args for a kernel thread, got %q", d.Args)
+	}
+
+	if _, err := source.(DetailSource).ProcessDetail(context.Background(), 99999); err == nil {
+		t.Error("Expected error for a missing process")
+	}
+}
+
+func TestLiveSource_ProcessDetail(t *testing.T) {
+	d, err := NewLiveSource(CPUPerCore).ProcessDetail(context.Background(), int32(os.Getpid()))
+	if err != nil {
+		t.Fatalf("ProcessDetail() вернул ошибку: %v", err)
+	}
+	exe, _ := os.Executable()
+	cwd, _ := os.Getwd()
+	if d.Exe != exe || d.Cwd != cwd || len(d.Environ) == 0 || len(d.Limits) == 0 {
+		t.Errorf("Unexpected detail of own process: exe %q cwd %q, %d env, %d limits",
+			d.Exe, d.Cwd, len(d.Environ), len(d.Limits))
+	}
+}
@@ -0,0 +1,70 @@
+//go:build !linux
+// +build !linux
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// limitNames - названия ресурсов в том же виде, что и в /proc/[pid]/limits
+var limitNames = map[int32]struct{ name, units string }{
+	process.RLIMIT_CPU:     {"Max cpu time", "seconds"},
+	process.RLIMIT_FSIZE:   {"Max file size", "bytes"},
+	process.RLIMIT_DATA:    {"Max data size", "bytes"},
+	process.RLIMIT_STACK:   {"Max stack size", "bytes"},
+	process.RLIMIT_CORE:    {"Max core file size", "bytes"},
+	process.RLIMIT_RSS:     {"Max resident set", "bytes"},
+	process.RLIMIT_NPROC:   {"Max processes", "processes"},
+	process.RLIMIT_NOFILE:  {"Max open files", "files"},
+	process.RLIMIT_MEMLOCK: {"Max locked memory", "bytes"},
+	process.RLIMIT_AS:      {"Max address space", "bytes"},
+}
+
+// readProcessDetail читает сведения о процессе через gopsutil
+func readProcessDetail(ctx context.Context, pid int32) (*ProcessDetail, error) {
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read process %d: %v", pid, err)
+	}
+	d := &ProcessDetail{PID: pid}
+	if uids, err := p.UidsWithContext(ctx); err == nil && len(uids) >= 2 {
+		d.UID, d.EUID = uids[0], uids[1]
+		d.User = lookupUser(d.UID)
+	}
+	d.Args, _ = p.CmdlineSliceWithContext(ctx)
+	if d.Exe, err = p.ExeWithContext(ctx); err != nil {
+		d.setError("exe", err)
+	}
+	if d.Cwd, err = p.CwdWithContext(ctx); err != nil {
+		d.setError("cwd", err)
+	}
+	if d.Environ, err = p.EnvironWithContext(ctx); err != nil {
+		d.setError("environ", err)
+	}
+	limits, err := p.RlimitWithContext(ctx)
+	if err != nil {
+		d.setError("limits", err)
+	}
+	for _, l := range limits {
+		names, ok := limitNames[l.Resource]
+		if !ok {
+			continue
+		}
+		d.Limits = append(d.Limits, Limit{Name: names.name, Soft: formatLimit(l.Soft), Hard: formatLimit(l.Hard), Units: names.units})
+	}
+	return d, nil
+}
+
+// formatLimit возвращает значение ограничения как в /proc/[pid]/limits
+func formatLimit(v uint64) string {
+	if v == math.MaxUint64 || v == math.MaxInt64 {
+		return "unlimited"
+	}
+	return strconv.FormatUint(v, 10)
+}
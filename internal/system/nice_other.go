@@ -0,0 +1,13 @@
+//go:build !unix
+
+package system
+
+import "errors"
+
+// errNoNice - nice задается через setpriority(2), которого нет вне Unix
+var errNoNice = errors.New("changing nice is only supported on Unix")
+
+// setNice на этих платформах не поддерживается
+func setNice(pid int32, nice int32) error {
+	return errNoNice
+}
@@ -0,0 +1,10 @@
+//go:build unix
+
+package system
+
+import "syscall"
+
+// setNice задает nice процесса системным вызовом setpriority(2)
+func setNice(pid int32, nice int32) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, int(pid), int(nice))
+}
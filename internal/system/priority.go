@@ -0,0 +1,131 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// Диапазон значений nice
+const (
+	MinNice = -20
+	MaxNice = 19
+)
+
+// IOClass - класс планирования ввода-вывода (ioprio)
+type IOClass int32
+
+// Классы ввода-вывода в нумерации ядра Linux
+const (
+	IOClassNone       IOClass = iota // класс не задан, приоритет следует из nice
+	IOClassRealtime                  // доступ к диску в первую очередь, только root
+	IOClassBestEffort                // обычный класс с уровнями 0-7
+	IOClassIdle                      // доступ к диску, только когда он никому не нужен
+)
+
+// MaxIOLevel - наименьший по приоритету уровень классов realtime и best-effort
+const MaxIOLevel = 7
+
+// IOPriority - класс и уровень приоритета ввода-вывода. Меньший уровень
+// означает больший приоритет.
+type IOPriority struct {
+	Class IOClass
+	Level int32
+}
+
+// Effective возвращает приоритет, с которым ядро планирует ввод-вывод процесса:
+// без заданного класса это best-effort с уровнем (nice + 20) / 5
+func (p IOPriority) Effective(nice int32) IOPriority {
+	if p.Class != IOClassNone {
+		return p
+	}
+	return IOPriority{Class: IOClassBestEffort, Level: (nice - MinNice) / 5}
+}
+
+// String возвращает приоритет в виде htop: B4 (best-effort 4), R0 (realtime 0),
+// id (idle) или "-" без заданного класса
+func (p IOPriority) String() string {
+	switch p.Class {
+	case IOClassRealtime:
+		return fmt.Sprintf("R%d", p.Level)
+	case IOClassBestEffort:
+		return fmt.Sprintf("B%d", p.Level)
+	case IOClassIdle:
+		return "id"
+	}
+	return "-"
+}
+
+// validate проверяет класс и уровень перед изменением приоритета
+func (p IOPriority) validate() error {
+	switch p.Class {
+	case IOClassNone, IOClassIdle:
+		return nil
+	case IOClassRealtime, IOClassBestEffort:
+		if p.Level < 0 || p.Level > MaxIOLevel {
+			return fmt.Errorf("invalid I/O priority level %d: must be between 0 and %d", p.Level, MaxIOLevel)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid I/O priority class %d", p.Class)
+}
+
+// PrioritySource - Source, умеющий менять приоритеты процессов. Источники
+// без доступа к процессам (запись сеанса) этот интерфейс не реализуют.
+type PrioritySource interface {
+	// SetNice задает nice процесса
+	SetNice(pid int32, nice int32) error
+	// SetIOPriority задает класс и уровень приоритета ввода-вывода процесса
+	SetIOPriority(pid int32, prio IOPriority) error
+}
+
+// SetNice задает nice процесса. Уменьшить nice (поднять приоритет) может только
+// root или владелец CAP_SYS_NICE, изменить чужой процесс - только root.
+func SetNice(pid int32, nice int32) error {
+	if nice < MinNice || nice > MaxNice {
+		return fmt.Errorf("invalid nice %d: must be between %d and %d", nice, MinNice, MaxNice)
+	}
+	if err := setNice(pid, nice); err != nil {
+		return priorityError(fmt.Sprintf("set nice %d", nice), pid, err)
+	}
+	return nil
+}
+
+// SetIOPriority задает приоритет ввода-вывода процесса. Класс realtime доступен
+// только root, изменить чужой процесс - только root.
+func SetIOPriority(pid int32, prio IOPriority) error {
+	if err := prio.validate(); err != nil {
+		return err
+	}
+	if err := setIOPriority(pid, prio); err != nil {
+		return priorityError(fmt.Sprintf("set I/O priority %s", prio), pid, err)
+	}
+	return nil
+}
+
+// priorityError поясняет ошибку изменения приоритета: чаще всего это нехватка прав
+func priorityError(action string, pid int32, err error) error {
+	return actionError(action, pid, err, "raising priority or changing processes of other users requires root")
+}
+
+// actionError поясняет ошибку действия над процессом. При нехватке прав
+// к сообщению добавляется hint - чьи права нужны.
+func actionError(action string, pid int32, err error, hint string) error {
+	switch {
+	case errors.Is(err, syscall.EPERM), errors.Is(err, syscall.EACCES):
+		return fmt.Errorf("cannot %s for process %d: permission denied (%s): %w", action, pid, hint, err)
+	case errors.Is(err, syscall.ESRCH):
+		return fmt.Errorf("cannot %s for process %d: process not found: %w", action, pid, err)
+	}
+	return fmt.Errorf("cannot %s for process %d: %w", action, pid, err)
+}
+
+// SetNice задает nice процесса текущей машины
+func (s *LiveSource) SetNice(pid int32, nice int32) error {
+	return SetNice(pid, nice)
+}
+
+// SetIOPriority задает приоритет ввода-вывода процесса текущей машины
+func (s *LiveSource) SetIOPriority(pid int32, prio IOPriority) error {
+	return SetIOPriority(pid, prio)
+}
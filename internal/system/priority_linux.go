@@ -0,0 +1,90 @@
+package system
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// readPriority читает приоритет планировщика и nice из /proc/[pid]/stat.
+// gopsutil возвращает nice через getpriority(2) без преобразования ядра (20 - nice),
+// а приоритет не возвращает вовсе.
+func readPriority(pid int32) (priority, nice int32, err error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, 0, err
+	}
+	return parseStatPriority(data)
+}
+
+// parseStatPriority извлекает поля priority (18) и nice (19) из содержимого
+// /proc/[pid]/stat. Имя процесса в скобках может содержать пробелы и скобки,
+// поэтому поля отсчитываются от последней закрывающей скобки.
+func parseStatPriority(data []byte) (priority, nice int32, err error) {
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return 0, 0, fmt.Errorf("malformed stat: no command name")
+	}
+	// После ")" идут поля начиная с 3-го (state)
+	fields := bytes.Fields(data[end+1:])
+	const priorityIdx, niceIdx = 18 - 3, 19 - 3
+	if len(fields) <= niceIdx {
+		return 0, 0, fmt.Errorf("malformed stat: %d fields", len(fields)+2)
+	}
+	p, err := strconv.ParseInt(string(fields[priorityIdx]), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed stat priority: %v", err)
+	}
+	n, err := strconv.ParseInt(string(fields[niceIdx]), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed stat nice: %v", err)
+	}
+	return int32(p), int32(n), nil
+}
+
+// Параметры системных вызовов ioprio_get и ioprio_set
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+	ioprioLevelMask  = 1<<ioprioClassShift - 1
+)
+
+// readIOPriority читает приоритет ввода-вывода процесса
+func readIOPriority(pid int32) (IOPriority, error) {
+	value, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(pid), 0)
+	if errno != 0 {
+		return IOPriority{}, errno
+	}
+	return decodeIOPriority(int32(value)), nil
+}
+
+// setIOPriority задает приоритет ввода-вывода процесса
+func setIOPriority(pid int32, prio IOPriority) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(pid), uintptr(encodeIOPriority(prio)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// decodeIOPriority разбирает значение ioprio: класс в старших битах, уровень
+// в младших. Для idle и отсутствующего класса уровень не используется,
+// хотя некоторые ядра его сообщают.
+func decodeIOPriority(value int32) IOPriority {
+	class := IOClass(value >> ioprioClassShift)
+	if class == IOClassNone || class == IOClassIdle {
+		return IOPriority{Class: class}
+	}
+	return IOPriority{Class: class, Level: value & ioprioLevelMask}
+}
+
+// encodeIOPriority собирает значение ioprio. Для idle и отсутствующего класса
+// уровень не используется.
+func encodeIOPriority(prio IOPriority) int32 {
+	if prio.Class == IOClassNone || prio.Class == IOClassIdle {
+		return int32(prio.Class) << ioprioClassShift
+	}
+	return int32(prio.Class)<<ioprioClassShift | prio.Level
+}
@@ -0,0 +1,143 @@
+package system
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestParseStatPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		stat     string
+		priority int32
+		nice     int32
+		wantErr  bool
+	}{
+		{
+			name:     "Regular process",
+			stat:     "1234 (bash) S 1 1234 1234 34816 1234 4194304 1000 0 0 0 10 5 0 0 20 0 1 0 100 10000000 500 18446744073709551615",
+			priority: 20,
+			nice:     0,
+		},
+		{
+			name:     "Name with spaces and parentheses",
+			stat:     "42 (Web Content (x)) R 1 42 42 0 -1 0 0 0 0 0 0 0 0 0 39 19 12 0 100 0 0 0",
+			priority: 39,
+			nice:     19,
+		},
+		{
+			name:     "Realtime priority",
+			stat:     "7 (migration/0) S 2 0 0 0 -1 69238848 0 0 0 0 0 0 0 0 -100 0 1 0 3 0 0 0",
+			priority: -100,
+			nice:     0,
+		},
+		{name: "Truncated", stat: "1 (init) S 0 1", wantErr: true},
+		{name: "No command", stat: "garbage", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priority, nice, err := parseStatPriority([]byte(tt.stat))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error for malformed stat")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseStatPriority() вернула ошибку: %v", err)
+			}
+			if priority != tt.priority || nice != tt.nice {
+				t.Errorf("parseStatPriority() = (%d, %d), want (%d, %d)", priority, nice, tt.priority, tt.nice)
+			}
+		})
+	}
+}
+
+func TestReadPriority_Self(t *testing.T) {
+	_, nice, err := readPriority(int32(os.Getpid()))
+	if err != nil {
+		t.Fatalf("readPriority() вернула ошибку: %v", err)
+	}
+	if nice < -20 || nice > 19 {
+		t.Errorf("Некорректное значение nice: %d", nice)
+	}
+}
+
+func TestIOPriorityEncoding(t *testing.T) {
+	tests := []IOPriority{
+		{Class: IOClassNone},
+		{Class: IOClassRealtime, Level: 0},
+		{Class: IOClassBestEffort, Level: 4},
+		{Class: IOClassBestEffort, Level: 7},
+		{Class: IOClassIdle},
+	}
+	for _, prio := range tests {
+		if got := decodeIOPriority(encodeIOPriority(prio)); got != prio {
+			t.Errorf("decodeIOPriority(encodeIOPriority(%+v)) = %+v", prio, got)
+		}
+	}
+	// Значение ioprio best-effort 4, как его возвращает ядро
+	if got := decodeIOPriority(2<<13 | 4); got != (IOPriority{Class: IOClassBestEffort, Level: 4}) {
+		t.Errorf("decodeIOPriority(be/4) = %+v", got)
+	}
+	if got := decodeIOPriority(4); got != (IOPriority{Class: IOClassNone}) {
+		t.Errorf("Expected the level of an unset class to be dropped, got %+v", got)
+	}
+}
+
+func TestReadIOPriority_Self(t *testing.T) {
+	prio, err := readIOPriority(int32(os.Getpid()))
+	if err != nil {
+		t.Fatalf("readIOPriority() вернула ошибку: %v", err)
+	}
+	if prio.Class < IOClassNone || prio.Class > IOClassIdle {
+		t.Errorf("Некорректный класс ввода-вывода: %+v", prio)
+	}
+}
+
+func TestSetNice_Self(t *testing.T) {
+	pid := int32(os.Getpid())
+	_, nice, err := readPriority(pid)
+	if err != nil {
+		t.Fatalf("readPriority() вернула ошибку: %v", err)
+	}
+	// Текущее значение можно задать без дополнительных прав
+	if err := SetNice(pid, nice); err != nil {
+		t.Errorf("SetNice() to the current value failed: %v", err)
+	}
+	prio, err := readIOPriority(pid)
+	if err != nil {
+		t.Fatalf("readIOPriority() вернула ошибку: %v", err)
+	}
+	if prio.Class != IOClassRealtime {
+		if err := SetIOPriority(pid, prio); err != nil {
+			t.Errorf("SetIOPriority() to the current value failed: %v", err)
+		}
+	}
+}
+
+func TestSetNice_Errors(t *testing.T) {
+	if err := SetNice(int32(os.Getpid()), 20); err == nil {
+		t.Error("Expected error for nice out of range")
+	}
+	if err := SetIOPriority(int32(os.Getpid()), IOPriority{Class: IOClassBestEffort, Level: 8}); err == nil {
+		t.Error("Expected error for I/O level out of range")
+	}
+	if err := SetIOPriority(int32(os.Getpid()), IOPriority{Class: 5}); err == nil {
+		t.Error("Expected error for unknown I/O class")
+	}
+
+	// PID вне диапазона ядра не может принадлежать процессу
+	err := SetNice(1<<30, 0)
+	if !errors.Is(err, syscall.ESRCH) || !strings.Contains(err.Error(), "process not found") {
+		t.Errorf("Expected process not found error, got %v", err)
+	}
+	err = SetIOPriority(1<<30, IOPriority{Class: IOClassIdle})
+	if !errors.Is(err, syscall.ESRCH) {
+		t.Errorf("Expected ESRCH for a missing process, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build !linux
+// +build !linux
+
+package system
+
+import (
+	"errors"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// readPriority возвращает nice процесса. Приоритет планировщика на этих
+// платформах не поддерживается и вычисляется как в Linux (20 + nice).
+func readPriority(pid int32) (priority, nice int32, err error) {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return 0, 0, err
+	}
+	nice, err = p.Nice()
+	if err != nil {
+		return 0, 0, err
+	}
+	return 20 + nice, nice, nil
+}
+
+// errNoIOPriority - приоритет ввода-вывода есть только в Linux
+var errNoIOPriority = errors.New("I/O priority is only supported on Linux")
+
+// readIOPriority на этих платформах не поддерживается
+func readIOPriority(pid int32) (IOPriority, error) {
+	return IOPriority{}, errNoIOPriority
+}
+
+// setIOPriority на этих платформах не поддерживается
+func setIOPriority(pid int32, prio IOPriority) error {
+	return errNoIOPriority
+}
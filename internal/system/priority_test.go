@@ -0,0 +1,42 @@
+package system
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestIOPriority_Effective(t *testing.T) {
+	tests := []struct {
+		prio IOPriority
+		nice int32
+		want string
+	}{
+		{IOPriority{}, 0, "B4"},
+		{IOPriority{}, -20, "B0"},
+		{IOPriority{}, 19, "B7"},
+		{IOPriority{Class: IOClassBestEffort, Level: 2}, 19, "B2"},
+		{IOPriority{Class: IOClassRealtime, Level: 0}, 0, "R0"},
+		{IOPriority{Class: IOClassIdle}, 0, "id"},
+	}
+	for _, tt := range tests {
+		if got := tt.prio.Effective(tt.nice).String(); got != tt.want {
+			t.Errorf("%+v.Effective(%d) = %s, want %s", tt.prio, tt.nice, got, tt.want)
+		}
+	}
+	if got := (IOPriority{}).String(); got != "-" {
+		t.Errorf("Expected - for an unset class, got %s", got)
+	}
+}
+
+func TestPriorityError(t *testing.T) {
+	err := priorityError("set nice -5", 42, syscall.EPERM)
+	if !errors.Is(err, syscall.EPERM) {
+		t.Errorf("Expected the cause to be kept, got %v", err)
+	}
+	want := "cannot set nice -5 for process 42: permission denied " +
+		"(raising priority or changing processes of other users requires root): operation not permitted"
+	if err.Error() != want {
+		t.Errorf("priorityError() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,447 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// procStat - поля /proc/[pid]/stat, нужные для списка процессов
+type procStat struct {
+	name       string
+	state      byte
+	ppid       int32
+	utime      uint64 // в тиках
+	stime      uint64 // в тиках
+	priority   int32
+	nice       int32
+	threads    int32
+	startTicks uint64 // время запуска в тиках после загрузки системы
+}
+
+// procStatic - неизменные за время жизни процесса данные. Запись действительна,
+// пока совпадает время запуска: иначе PID занят новым процессом.
+type procStatic struct {
+	startTicks uint64
+	cmdline    string
+	ioDenied   bool // счетчики ввода-вывода недоступны, повторно не читаем
+}
+
+// ProcCollector собирает список процессов, читая /proc напрямую. В отличие от
+// GetProcessList, он не создает объект gopsutil на каждый процесс, читает
+// stat, statm и status в переиспользуемый буфер и кэширует командную строку
+// между вызовами. Методы можно вызывать из разных горутин.
+type ProcCollector struct {
+	mu       sync.Mutex
+	root     string
+	live     bool // root - /proc текущей машины, доступны системные вызовы
+	sampler  *CPUSampler
+	buf      []byte
+	fields   [][]byte
+	static   map[int32]procStatic
+	clkTck   float64
+	pageSize uint64
+	bootTime time.Time // читается при первом вызове
+	memTotal uint64
+	now      func() time.Time
+}
+
+// NewProcCollector создает ProcCollector для /proc текущей машины
+func NewProcCollector(sampler *CPUSampler) *ProcCollector {
+	return NewProcCollectorAt("/proc", sampler)
+}
+
+// NewProcCollectorAt создает ProcCollector, читающий procfs из каталога root:
+// /proc хоста, смонтированный в контейнер, или тестовое дерево файлов
+func NewProcCollectorAt(root string, sampler *CPUSampler) *ProcCollector {
+	return &ProcCollector{
+		root:     root,
+		live:     filepath.Clean(root) == "/proc",
+		sampler:  sampler,
+		buf:      make([]byte, 0, 4096),
+		static:   make(map[int32]procStatic),
+		clkTck:   cpu.ClocksPerSec,
+		pageSize: uint64(os.Getpagesize()),
+		now:      time.Now,
+	}
+}
+
+// newProcessCollector возвращает способ получения списка процессов для LiveSource
+func newProcessCollector(sampler *CPUSampler) processCollector {
+	return NewProcCollector(sampler)
+}
+
+// Processes возвращает список процессов. Процессы, завершившиеся во время
+// обхода, пропускаются.
+func (c *ProcCollector) Processes(ctx context.Context) ([]ProcessInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.bootTime.IsZero() {
+		if err := c.readSystemInfo(); err != nil {
+			return nil, err
+		}
+	}
+
+	pids, err := c.pids()
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	processList := make([]ProcessInfo, 0, len(pids))
+	cpuTimes := make([]CPUTime, 0, len(pids))
+	seen := make(map[int32]bool, len(pids))
+	for _, pid := range pids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		info, stat, ok := c.process(pid)
+		if !ok {
+			continue
+		}
+		seen[pid] = true
+		processList = append(processList, info)
+		cpuTimes = append(cpuTimes, CPUTime{
+			PID:        pid,
+			CreateTime: info.StartTime.UnixMilli(),
+			Seconds:    float64(stat.utime+stat.stime) / c.clkTck,
+		})
+	}
+
+	// Кэш завершившихся процессов больше не нужен
+	for pid := range c.static {
+		if !seen[pid] {
+			delete(c.static, pid)
+		}
+	}
+
+	percents := c.sampler.Sample(now, cpuTimes)
+	for i := range processList {
+		processList[i].CPU = percents[processList[i].PID]
+	}
+	return processList, nil
+}
+
+// process читает данные одного процесса. ok == false, если процесс
+// завершился или его stat не удалось разобрать.
+func (c *ProcCollector) process(pid int32) (info ProcessInfo, stat procStat, ok bool) {
+	data, err := c.read(pid, "stat")
+	if err != nil {
+		return info, stat, false
+	}
+	if stat, err = c.parseStat(data); err != nil {
+		return info, stat, false
+	}
+
+	static, cached := c.static[pid]
+	if !cached || static.startTicks != stat.startTicks {
+		static = procStatic{startTicks: stat.startTicks}
+		if data, err := c.read(pid, "cmdline"); err == nil {
+			static.cmdline = parseCmdline(data)
+		}
+	}
+
+	info = ProcessInfo{
+		PID:       pid,
+		PPID:      stat.ppid,
+		Name:      stat.name,
+		Status:    statusName(stat.state),
+		Cmdline:   static.cmdline,
+		Threads:   stat.threads,
+		Nice:      stat.nice,
+		Priority:  stat.priority,
+		CPUTime:   time.Duration(float64(stat.utime+stat.stime) / c.clkTck * float64(time.Second)),
+		StartTime: c.startTime(stat.startTicks),
+	}
+
+	if data, err := c.read(pid, "statm"); err == nil {
+		if size, resident, err := parseStatm(data); err == nil {
+			info.VSZ = size * c.pageSize
+			info.RSS = resident * c.pageSize
+			if c.memTotal > 0 {
+				info.Memory = float32(100 * float64(info.RSS) / float64(c.memTotal))
+			}
+		}
+	}
+	if data, err := c.read(pid, "status"); err == nil {
+		if uid, ok := parseStatusUID(data); ok {
+			info.User = lookupUser(uid)
+		}
+	}
+	// Счетчики ввода-вывода чужих процессов доступны только root
+	if !static.ioDenied {
+		if data, err := c.read(pid, "io"); err == nil {
+			info.ReadBytes, info.WriteBytes = parseIO(data)
+		} else if errors.Is(err, os.ErrPermission) {
+			static.ioDenied = true
+		}
+	}
+	// Приоритет ввода-вывода не отражен в procfs, его сообщает только ядро
+	if c.live {
+		info.IOPriority, _ = readIOPriority(pid)
+	}
+	if data, err := c.read(pid, "cgroup"); err == nil {
+		info.Cgroup = parseCgroup(string(data))
+	}
+
+	c.static[pid] = static
+	return info, stat, true
+}
+
+// StartTime возвращает время запуска процесса. Оно вычисляется так же, как
+// в Processes, поэтому его можно сравнивать со временем запуска из списка,
+// чтобы убедиться, что PID не занят новым процессом.
+func (c *ProcCollector) StartTime(pid int32) (time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.bootTime.IsZero() {
+		if err := c.readSystemInfo(); err != nil {
+			return time.Time{}, err
+		}
+	}
+	data, err := c.read(pid, "stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	stat, err := c.parseStat(data)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.startTime(stat.startTicks), nil
+}
+
+// startTime переводит время запуска в тиках после загрузки системы в абсолютное
+func (c *ProcCollector) startTime(ticks uint64) time.Time {
+	return c.bootTime.Add(time.Duration(float64(ticks) / c.clkTck * float64(time.Second)))
+}
+
+// pids возвращает PID всех процессов из каталога root
+func (c *ProcCollector) pids() ([]int32, error) {
+	dir, err := os.Open(c.root)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+	pids := make([]int32, 0, len(names))
+	for _, name := range names {
+		if pid, err := strconv.ParseInt(name, 10, 32); err == nil && pid > 0 {
+			pids = append(pids, int32(pid))
+		}
+	}
+	return pids, nil
+}
+
+// read читает файл процесса в общий буфер. Результат действителен до
+// следующего вызова read.
+func (c *ProcCollector) read(pid int32, name string) ([]byte, error) {
+	return c.readFile(filepath.Join(c.root, strconv.Itoa(int(pid)), name))
+}
+
+// readFile читает файл в общий буфер, увеличивая его при необходимости.
+// Файлы /proc не сообщают размер, поэтому читаем до EOF.
+func (c *ProcCollector) readFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c.buf = c.buf[:0]
+	for {
+		if len(c.buf) == cap(c.buf) {
+			c.buf = append(c.buf, 0)[:len(c.buf)]
+		}
+		n, err := f.Read(c.buf[len(c.buf):cap(c.buf)])
+		c.buf = c.buf[:len(c.buf)+n]
+		if err == io.EOF {
+			return c.buf, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readSystemInfo читает время загрузки системы и объем памяти
+func (c *ProcCollector) readSystemInfo() error {
+	data, err := c.readFile(filepath.Join(c.root, "stat"))
+	if err != nil {
+		return fmt.Errorf("failed to read boot time: %v", err)
+	}
+	btime, ok := findField(data, "btime")
+	if !ok {
+		return fmt.Errorf("failed to read boot time: no btime in %s/stat", c.root)
+	}
+	c.bootTime = time.Unix(int64(btime), 0)
+
+	data, err = c.readFile(filepath.Join(c.root, "meminfo"))
+	if err != nil {
+		return fmt.Errorf("failed to read memory info: %v", err)
+	}
+	if total, ok := findField(data, "MemTotal:"); ok {
+		c.memTotal = total * 1024 // значение в кБ
+	}
+	return nil
+}
+
+// parseStat разбирает /proc/[pid]/stat. Имя процесса в скобках может
+// содержать пробелы и скобки, поэтому поля отсчитываются от последней
+// закрывающей скобки.
+func (c *ProcCollector) parseStat(data []byte) (procStat, error) {
+	var stat procStat
+	start := bytes.IndexByte(data, '(')
+	end := bytes.LastIndexByte(data, ')')
+	if start < 0 || end < start {
+		return stat, fmt.Errorf("malformed stat: no command name")
+	}
+	stat.name = string(data[start+1 : end])
+
+	// После ")" идут поля начиная с 3-го (state)
+	c.fields = splitFields(c.fields[:0], data[end+1:])
+	field := func(n int) []byte { return c.fields[n-3] }
+	if len(c.fields) < 22-2 {
+		return stat, fmt.Errorf("malformed stat: %d fields", len(c.fields)+2)
+	}
+
+	var errs []error
+	parse := func(n int) uint64 {
+		v, err := strconv.ParseUint(string(field(n)), 10, 64)
+		errs = append(errs, err)
+		return v
+	}
+	parseSigned := func(n int) int32 {
+		v, err := strconv.ParseInt(string(field(n)), 10, 32)
+		errs = append(errs, err)
+		return int32(v)
+	}
+	stat.state = field(3)[0]
+	stat.ppid = parseSigned(4)
+	stat.utime = parse(14)
+	stat.stime = parse(15)
+	stat.priority = parseSigned(18)
+	stat.nice = parseSigned(19)
+	stat.threads = parseSigned(20)
+	stat.startTicks = parse(22)
+	if err := errors.Join(errs...); err != nil {
+		return stat, fmt.Errorf("malformed stat: %v", err)
+	}
+	return stat, nil
+}
+
+// splitFields добавляет в dst поля data, разделенные пробелами. В отличие
+// от bytes.Fields переиспользует dst между вызовами.
+func splitFields(dst [][]byte, data []byte) [][]byte {
+	start := -1
+	for i, b := range data {
+		space := b == ' ' || b == '\n' || b == '\t'
+		switch {
+		case space && start >= 0:
+			dst = append(dst, data[start:i])
+			start = -1
+		case !space && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		dst = append(dst, data[start:])
+	}
+	return dst
+}
+
+// parseStatm возвращает размер виртуальной памяти и резидентной памяти
+// в страницах из /proc/[pid]/statm
+func parseStatm(data []byte) (size, resident uint64, err error) {
+	fields := bytes.Fields(data)
+	if len(fields) < 2 {
+		return 0, 0, fmt.Errorf("malformed statm: %d fields", len(fields))
+	}
+	if size, err = strconv.ParseUint(string(fields[0]), 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("malformed statm: %v", err)
+	}
+	if resident, err = strconv.ParseUint(string(fields[1]), 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("malformed statm: %v", err)
+	}
+	return size, resident, nil
+}
+
+// parseStatusUID возвращает реальный UID из строки "Uid:" файла /proc/[pid]/status
+func parseStatusUID(data []byte) (int32, bool) {
+	uid, ok := findField(data, "Uid:")
+	return int32(uid), ok
+}
+
+// parseIO возвращает прочитанные и записанные на диск байты из /proc/[pid]/io
+func parseIO(data []byte) (read, write uint64) {
+	read, _ = findField(data, "read_bytes:")
+	write, _ = findField(data, "write_bytes:")
+	return read, write
+}
+
+// parseCmdline превращает аргументы, разделенные нулевыми байтами, в строку
+// через пробел, как gopsutil
+func parseCmdline(data []byte) string {
+	args := bytes.FieldsFunc(data, func(r rune) bool { return r == 0 })
+	return string(bytes.Join(args, []byte(" ")))
+}
+
+// findField возвращает первое число строки, начинающейся с key
+func findField(data []byte, key string) (uint64, bool) {
+	for len(data) > 0 {
+		line := data
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line, data = data[:i], data[i+1:]
+		} else {
+			data = nil
+		}
+		if !bytes.HasPrefix(line, []byte(key)) {
+			continue
+		}
+		fields := bytes.Fields(line[len(key):])
+		if len(fields) == 0 {
+			return 0, false
+		}
+		v, err := strconv.ParseUint(string(fields[0]), 10, 64)
+		return v, err == nil
+	}
+	return 0, false
+}
+
+// statusName возвращает состояние процесса в обозначениях gopsutil
+func statusName(state byte) string {
+	switch state {
+	case 'R':
+		return process.Running
+	case 'S':
+		return process.Sleep
+	case 'D':
+		return process.Blocked
+	case 'T', 't':
+		return process.Stop
+	case 'Z':
+		return process.Zombie
+	case 'I':
+		return process.Idle
+	case 'W':
+		return process.Wait
+	case 'L':
+		return process.Lock
+	}
+	return strings.ToLower(string(state))
+}
@@ -0,0 +1,168 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+func TestProcCollector_ParseStat(t *testing.T) {
+	c := NewProcCollector(NewCPUSampler(CPUPerCore))
+	data := []byte("42 (my (weird) name) S 1 42 42 0 -1 4194560 100 0 0 0 250 50 0 0 20 0 3 0 12345 1000000 200 " +
+		"18446744073709551615 1 1 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0\n")
+
+	stat, err := c.parseStat(data)
+	if err != nil {
+		t.Fatalf("parseStat() вернул ошибку: %v", err)
+	}
+	want := procStat{name: "my (weird) name", state: 'S', ppid: 1, utime: 250, stime: 50,
+		priority: 20, nice: 0, threads: 3, startTicks: 12345}
+	if stat != want {
+		t.Errorf("parseStat() = %+v, want %+v", stat, want)
+	}
+
+	for _, bad := range []string{"42 no name", "42 (x) S 1 2 3", "42 (x) S abc 42 42 0 -1 0 0 0 0 0 1 1 0 0 20 0 1 0 1 0 0"} {
+		if _, err := c.parseStat([]byte(bad)); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseProcFiles(t *testing.T) {
+	size, resident, err := parseStatm([]byte("2500 300 100 1 0 200 0\n"))
+	if err != nil || size != 2500 || resident != 300 {
+		t.Errorf("parseStatm() = %d, %d, %v", size, resident, err)
+	}
+	if _, _, err := parseStatm([]byte("12")); err == nil {
+		t.Error("Expected error for short statm")
+	}
+
+	status := []byte("Name:\tbash\nUmask:\t0022\nState:\tS (sleeping)\nUid:\t1000\t0\t0\t0\nGid:\t100\t100\t100\t100\n")
+	if uid, ok := parseStatusUID(status); !ok || uid != 1000 {
+		t.Errorf("parseStatusUID() = %d, %v, want real UID 1000", uid, ok)
+	}
+
+	read, write := parseIO([]byte("rchar: 10\nwchar: 20\nread_bytes: 4096\nwrite_bytes: 8192\n"))
+	if read != 4096 || write != 8192 {
+		t.Errorf("parseIO() = %d, %d", read, write)
+	}
+
+	if got := parseCmdline([]byte("vim\x00notes.txt\x00\x00")); got != "vim notes.txt" {
+		t.Errorf("parseCmdline() = %q", got)
+	}
+}
+
+func TestStatusName(t *testing.T) {
+	for state, want := range map[byte]string{'R': "running", 'S': "sleep", 'D': "blocked", 't': "stop", 'Z': "zombie", 'I': "idle"} {
+		if got := statusName(state); got != want {
+			t.Errorf("statusName(%c) = %q, want %q", state, got, want)
+		}
+	}
+}
+
+func TestProcCollector_MatchesGopsutil(t *testing.T) {
+	c := NewProcCollector(NewCPUSampler(CPUPerCore))
+	processes, err := c.Processes(context.Background())
+	if err != nil {
+		t.Fatalf("Processes() вернул ошибку: %v", err)
+	}
+
+	self := int32(os.Getpid())
+	var info ProcessInfo
+	for _, p := range processes {
+		if p.PID == self {
+			info = p
+		}
+	}
+	if info.PID != self {
+		t.Fatalf("Own process %d not found", self)
+	}
+
+	p, err := process.NewProcess(self)
+	if err != nil {
+		t.Fatalf("NewProcess() вернул ошибку: %v", err)
+	}
+	name, _ := p.Name()
+	ppid, _ := p.Ppid()
+	cmdline, _ := p.Cmdline()
+	created, _ := p.CreateTime()
+	if info.Name != name || info.PPID != ppid || info.Cmdline != cmdline {
+		t.Errorf("Got %q/%d/%q, gopsutil %q/%d/%q", info.Name, info.PPID, info.Cmdline, name, ppid, cmdline)
+	}
+	if d := info.StartTime.Sub(time.UnixMilli(created)); d < -time.Second || d > time.Second {
+		t.Errorf("Start time %v differs from gopsutil %v", info.StartTime, time.UnixMilli(created))
+	}
+	if info.RSS == 0 || info.VSZ < info.RSS || info.Threads < 1 || info.User == "" || info.Memory <= 0 {
+		t.Errorf("Unexpected process info: %+v", info)
+	}
+}
+
+func TestProcCollector_CachesCmdline(t *testing.T) {
+	c := NewProcCollector(NewCPUSampler(CPUPerCore))
+	if _, err := c.Processes(context.Background()); err != nil {
+		t.Fatalf("Processes() вернул ошибку: %v", err)
+	}
+	self := int32(os.Getpid())
+	static, ok := c.static[self]
+	if !ok || static.cmdline == "" {
+		t.Fatalf("Expected cached command line for own process, got %+v", static)
+	}
+
+	// Запись с другим временем запуска считается чужим процессом и перечитывается
+	c.static[self] = procStatic{startTicks: static.startTicks + 1, cmdline: "stale"}
+	processes, _ := c.Processes(context.Background())
+	for _, p := range processes {
+		if p.PID == self && p.Cmdline == "stale" {
+			t.Error("Expected command line to be re-read after PID reuse")
+		}
+	}
+}
+
+func TestProcCollector_StartTime(t *testing.T) {
+	c := NewProcCollector(NewCPUSampler(CPUPerCore))
+	processes, err := c.Processes(context.Background())
+	if err != nil {
+		t.Fatalf("Processes() вернул ошибку: %v", err)
+	}
+	self := int32(os.Getpid())
+	for _, p := range processes {
+		if p.PID != self {
+			continue
+		}
+		// Время запуска совпадает со списком точно, иначе сверка PID перед сигналом не сработает
+		start, err := c.StartTime(self)
+		if err != nil || !start.Equal(p.StartTime) {
+			t.Errorf("StartTime() = %v, %v, want %v", start, err, p.StartTime)
+		}
+	}
+
+	if _, err := c.StartTime(1 << 30); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected ErrNotExist for a missing process, got %v", err)
+	}
+}
+
+func BenchmarkProcCollector(b *testing.B) {
+	c := NewProcCollector(NewCPUSampler(CPUPerCore))
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Processes(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetProcessList(b *testing.B) {
+	sampler := NewCPUSampler(CPUPerCore)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetProcessListWithSampler(sampler); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
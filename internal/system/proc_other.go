@@ -0,0 +1,46 @@
+//go:build !linux
+// +build !linux
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// gopsutilCollector собирает список процессов через gopsutil
+type gopsutilCollector struct {
+	sampler *CPUSampler
+}
+
+// newProcessCollector возвращает способ получения списка процессов для LiveSource.
+// Вне Linux список собирается через gopsutil.
+func newProcessCollector(sampler *CPUSampler) processCollector {
+	return &gopsutilCollector{sampler: sampler}
+}
+
+// Processes возвращает список процессов
+func (c *gopsutilCollector) Processes(ctx context.Context) ([]ProcessInfo, error) {
+	return GetProcessListWithContext(ctx, c.sampler)
+}
+
+// StartTime возвращает время запуска процесса с той же точностью, что и в списке
+func (c *gopsutilCollector) StartTime(pid int32) (time.Time, error) {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return time.Time{}, processNotRunning(pid, err)
+	}
+	created, err := p.CreateTime()
+	if err != nil {
+		return time.Time{}, processNotRunning(pid, err)
+	}
+	return time.UnixMilli(created), nil
+}
+
+// OpenProcSource доступен только в Linux
+func OpenProcSource(root string, mode CPUMode) (Source, error) {
+	return nil, fmt.Errorf("procfs %s is only supported on Linux", root)
+}
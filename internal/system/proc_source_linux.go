@@ -0,0 +1,218 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// cpuTicks - счетчики ядра из строки cpuN файла /proc/stat
+type cpuTicks struct {
+	total uint64
+	idle  uint64 // idle + iowait
+}
+
+// ProcSource - Source, читающий все данные из procfs в каталоге root:
+// загрузку ядер из stat, память из meminfo и процессы через ProcCollector
+type ProcSource struct {
+	mu        sync.Mutex
+	root      string
+	collector *ProcCollector
+	prev      []cpuTicks // счетчики предыдущего вызова CPUPercent
+}
+
+// OpenProcSource создает ProcSource для procfs в каталоге root. Загрузка CPU
+// процессов нормируется по количеству ядер из root/stat.
+func OpenProcSource(root string, mode CPUMode) (Source, error) {
+	s := &ProcSource{root: root}
+	count, err := s.CPUCount()
+	if err != nil {
+		return nil, err
+	}
+	sampler := NewCPUSampler(mode)
+	sampler.numCPU = count
+	s.collector = NewProcCollectorAt(root, sampler)
+	return s, nil
+}
+
+// CPUCount возвращает количество строк cpuN в root/stat
+func (s *ProcSource) CPUCount() (int, error) {
+	ticks, err := s.readCPUTicks()
+	if err != nil {
+		return 0, err
+	}
+	return len(ticks), nil
+}
+
+// CPUPercent возвращает загрузку каждого ядра с предыдущего вызова,
+// при первом вызове - с момента загрузки системы
+func (s *ProcSource) CPUPercent(ctx context.Context) ([]float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticks, err := s.readCPUTicks()
+	if err != nil {
+		return nil, err
+	}
+	percents := make([]float64, len(ticks))
+	for i, t := range ticks {
+		var prev cpuTicks
+		if i < len(s.prev) {
+			prev = s.prev[i]
+		}
+		// Счетчик, ушедший назад (ядро отключали, root сменили), не дает
+		// разницы: беззнаковое вычитание дало бы огромную загрузку. Замер
+		// становится новой точкой отсчета.
+		if t.total < prev.total || t.idle < prev.idle {
+			continue
+		}
+		total := float64(t.total - prev.total)
+		if total > 0 {
+			percents[i] = 100 * (total - float64(t.idle-prev.idle)) / total
+		}
+	}
+	s.prev = ticks
+	return percents, nil
+}
+
+// Memory возвращает данные о памяти из root/meminfo, вычисляя занятую память
+// так же, как gopsutil
+func (s *ProcSource) Memory(ctx context.Context) (*mem.VirtualMemoryStat, error) {
+	data, err := os.ReadFile(filepath.Join(s.root, "meminfo"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory info: %v", err)
+	}
+	kb := func(key string) uint64 {
+		v, _ := findField(data, key)
+		return v * 1024
+	}
+	m := &mem.VirtualMemoryStat{
+		Total:     kb("MemTotal:"),
+		Free:      kb("MemFree:"),
+		Available: kb("MemAvailable:"),
+		Buffers:   kb("Buffers:"),
+		Cached:    kb("Cached:") + kb("SReclaimable:"),
+		SwapTotal: kb("SwapTotal:"),
+		SwapFree:  kb("SwapFree:"),
+	}
+	if m.Total == 0 {
+		return nil, fmt.Errorf("failed to read memory info: no MemTotal in %s/meminfo", s.root)
+	}
+	m.Used = m.Total - m.Free - m.Buffers - m.Cached
+	m.UsedPercent = 100 * float64(m.Used) / float64(m.Total)
+	return m, nil
+}
+
+// LoadAverage возвращает среднюю загрузку из root/loadavg
+func (s *ProcSource) LoadAverage(ctx context.Context) (*load.AvgStat, error) {
+	data, err := os.ReadFile(filepath.Join(s.root, "loadavg"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read load average: %v", err)
+	}
+	fields := bytes.Fields(data)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("malformed %s/loadavg", s.root)
+	}
+	var values [3]float64
+	for i := range values {
+		if values[i], err = strconv.ParseFloat(string(fields[i]), 64); err != nil {
+			return nil, fmt.Errorf("malformed %s/loadavg: %v", s.root, err)
+		}
+	}
+	return &load.AvgStat{Load1: values[0], Load5: values[1], Load15: values[2]}, nil
+}
+
+// Processes возвращает список процессов
+func (s *ProcSource) Processes(ctx context.Context) ([]ProcessInfo, error) {
+	return s.collector.Processes(ctx)
+}
+
+// SendSignal отправляет сигнал процессу, убедившись, что PID не занят другим
+// процессом. Для procfs не текущей машины PID относятся к другой системе,
+// поэтому отправка запрещена.
+func (s *ProcSource) SendSignal(pid int32, start time.Time, sig syscall.Signal) error {
+	if !s.collector.live {
+		return fmt.Errorf("cannot send signals to processes of %s", s.root)
+	}
+	return signalProcess(pid, start, sig, s.collector.StartTime)
+}
+
+// SetNice задает nice процесса. Для procfs не текущей машины изменение запрещено.
+func (s *ProcSource) SetNice(pid int32, nice int32) error {
+	if !s.collector.live {
+		return fmt.Errorf("cannot change priority of processes of %s", s.root)
+	}
+	return SetNice(pid, nice)
+}
+
+// SetIOPriority задает приоритет ввода-вывода процесса. Для procfs не текущей
+// машины изменение запрещено.
+func (s *ProcSource) SetIOPriority(pid int32, prio IOPriority) error {
+	if !s.collector.live {
+		return fmt.Errorf("cannot change priority of processes of %s", s.root)
+	}
+	return SetIOPriority(pid, prio)
+}
+
+// Affinity возвращает привязку процесса к ядрам. Для procfs не текущей машины
+// привязка недоступна.
+func (s *ProcSource) Affinity(pid int32) ([]int, error) {
+	if !s.collector.live {
+		return nil, fmt.Errorf("cannot read CPU affinity of processes of %s", s.root)
+	}
+	return Affinity(pid)
+}
+
+// SetAffinity привязывает процесс к ядрам. Для procfs не текущей машины
+// изменение запрещено.
+func (s *ProcSource) SetAffinity(pid int32, cpus []int, allThreads bool) error {
+	if !s.collector.live {
+		return fmt.Errorf("cannot change CPU affinity of processes of %s", s.root)
+	}
+	return SetAffinity(pid, cpus, allThreads)
+}
+
+// readCPUTicks читает счетчики каждого ядра из root/stat
+func (s *ProcSource) readCPUTicks() ([]cpuTicks, error) {
+	data, err := os.ReadFile(filepath.Join(s.root, "stat"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CPU stats: %v", err)
+	}
+
+	var ticks []cpuTicks
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		// Строка "cpu " - сумма по всем ядрам, нужны только "cpuN"
+		if len(line) < 4 || !bytes.HasPrefix(line, []byte("cpu")) || line[3] == ' ' {
+			continue
+		}
+		fields := bytes.Fields(line)[1:]
+		var t cpuTicks
+		for i, f := range fields {
+			v, err := strconv.ParseUint(string(f), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("malformed %s/stat: %v", s.root, err)
+			}
+			// guest и guest_nice уже входят в user и nice
+			if i < 8 {
+				t.total += v
+			}
+			if i == 3 || i == 4 { // idle, iowait
+				t.idle += v
+			}
+		}
+		ticks = append(ticks, t)
+	}
+	if len(ticks) == 0 {
+		return nil, fmt.Errorf("malformed %s/stat: no cpu lines", s.root)
+	}
+	return ticks, nil
+}
@@ -0,0 +1,228 @@
+package system
+
+import (
+	"context"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Тестовые деревья procfs в testdata/proc:
+//   - basic: init, потоки ядра kthreadd и kworker, многопоточный процесс
+//     "web server" (8 потоков, nice 10, UID 4242, с environ, limits, exe и cwd)
+//     и его зомби;
+//   - reuse/1, reuse/2: два последовательных замера, между которыми PID 500
+//     занял новый процесс, а init получил 1 с процессорного времени.
+//
+// Время загрузки во всех деревьях - 1700000000, тик - 1/100 с, страница - 4096 байт.
+var fixtureBootTime = time.Unix(1700000000, 0)
+
+// fixtureCollector создает ProcCollector для тестового дерева с фиксированными
+// параметрами машины и часами
+func fixtureCollector(t *testing.T, tree string, now time.Time) *ProcCollector {
+	t.Helper()
+	sampler := NewCPUSampler(CPUPerCore)
+	sampler.numCPU = 4
+	c := NewProcCollectorAt(filepath.Join("testdata", "proc", tree), sampler)
+	c.clkTck = 100
+	c.pageSize = 4096
+	c.now = func() time.Time { return now }
+	return c
+}
+
+func processByPID(t *testing.T, processes []ProcessInfo, pid int32) ProcessInfo {
+	t.Helper()
+	for _, p := range processes {
+		if p.PID == pid {
+			return p
+		}
+	}
+	t.Fatalf("PID %d not found", pid)
+	return ProcessInfo{}
+}
+
+func TestProcCollector_BasicFixture(t *testing.T) {
+	c := fixtureCollector(t, "basic", fixtureBootTime.Add(1000*time.Second))
+	processes, err := c.Processes(context.Background())
+	if err != nil {
+		t.Fatalf("Processes() вернул ошибку: %v", err)
+	}
+	if len(processes) != 5 {
+		t.Fatalf("Expected 5 processes, got %d", len(processes))
+	}
+
+	init := processByPID(t, processes, 1)
+	want := ProcessInfo{
+		PID: 1, PPID: 0, Name: "systemd", Memory: 0.25, Status: "sleep", Cmdline: "/sbin/init splash",
+		User: lookupUser(0), RSS: 2500 * 4096, VSZ: 5000 * 4096, Threads: 1, Nice: 0, Priority: 20,
+		StartTime: fixtureBootTime, CPUTime: 40 * time.Second, ReadBytes: 1000, WriteBytes: 2000,
+		Cgroup: "/init.scope",
+	}
+	cpu := init.CPU
+	init.CPU = 0
+	if init != want {
+		t.Errorf("Unexpected init:\n got %+v\nwant %+v", init, want)
+	}
+	// 40 с процессорного времени за 1000 с жизни
+	if !almostEqual(cpu, 4) {
+		t.Errorf("Expected init CPU 4%%, got %v", cpu)
+	}
+
+	kthreadd := processByPID(t, processes, 2)
+	if kthreadd.Cmdline != "" || kthreadd.Name != "kthreadd" || kthreadd.RSS != 0 || kthreadd.PPID != 0 {
+		t.Errorf("Unexpected kernel thread: %+v", kthreadd)
+	}
+	kworker := processByPID(t, processes, 3)
+	if kworker.Status != "idle" || kworker.PPID != 2 || kworker.Name != "kworker/0:1-events" {
+		t.Errorf("Unexpected kworker: %+v", kworker)
+	}
+	if !kworker.StartTime.Equal(fixtureBootTime.Add(100 * time.Millisecond)) {
+		t.Errorf("Unexpected kworker start time: %v", kworker.StartTime)
+	}
+
+	server := processByPID(t, processes, 100)
+	if server.Name != "web server" || server.Threads != 8 || server.Nice != 10 || server.Priority != 30 ||
+		server.User != lookupUser(4242) || server.Cmdline != "/usr/bin/web-server --port 8080" {
+		t.Errorf("Unexpected multithreaded process: %+v", server)
+	}
+	if server.Memory != 2.5 || server.Cgroup != "/system.slice/web.service" {
+		t.Errorf("Unexpected memory or cgroup: %v, %q", server.Memory, server.Cgroup)
+	}
+	// Многопоточный процесс: 600 с за 500 с жизни - больше одного ядра
+	if !almostEqual(server.CPU, 120) {
+		t.Errorf("Expected multithreaded CPU 120%%, got %v", server.CPU)
+	}
+
+	zombie := processByPID(t, processes, 101)
+	if zombie.Status != "zombie" || zombie.Cmdline != "" || zombie.RSS != 0 || zombie.ReadBytes != 0 {
+		t.Errorf("Unexpected zombie: %+v", zombie)
+	}
+}
+
+func TestProcCollector_PIDReuseFixture(t *testing.T) {
+	c := fixtureCollector(t, "reuse/1", fixtureBootTime.Add(1000*time.Second))
+	first, err := c.Processes(context.Background())
+	if err != nil {
+		t.Fatalf("Processes() вернул ошибку: %v", err)
+	}
+	if job := processByPID(t, first, 500); job.Name != "old-job" || !almostEqual(job.CPU, 1) {
+		t.Errorf("Unexpected first job: %+v", job)
+	}
+
+	c.root = filepath.Join("testdata", "proc", "reuse", "2")
+	c.now = func() time.Time { return fixtureBootTime.Add(1002 * time.Second) }
+	second, err := c.Processes(context.Background())
+	if err != nil {
+		t.Fatalf("Processes() вернул ошибку: %v", err)
+	}
+
+	// Новый процесс с тем же PID: командная строка перечитана, CPU считается
+	// от его запуска (2 с за 100 с), а не от замера старого процесса
+	job := processByPID(t, second, 500)
+	if job.Name != "new-job" || job.Cmdline != "new-job --fresh" {
+		t.Errorf("Expected new process data after PID reuse, got %+v", job)
+	}
+	if !almostEqual(job.CPU, 2) {
+		t.Errorf("Expected reused PID CPU 2%%, got %v", job.CPU)
+	}
+	// init получил 1 с за 2 с между замерами
+	if init := processByPID(t, second, 1); !almostEqual(init.CPU, 50) {
+		t.Errorf("Expected init CPU 50%% over the interval, got %v", init.CPU)
+	}
+}
+
+func TestProcSource_Fixture(t *testing.T) {
+	source, err := OpenProcSource(filepath.Join("testdata", "proc", "basic"), CPUPerCore)
+	if err != nil {
+		t.Fatalf("OpenProcSource() вернул ошибку: %v", err)
+	}
+	ctx := context.Background()
+
+	if count, err := source.CPUCount(); err != nil || count != 4 {
+		t.Errorf("CPUCount() = %d, %v, want 4", count, err)
+	}
+	percents, err := source.CPUPercent(ctx)
+	if err != nil || len(percents) != 4 || !almostEqual(percents[0], 20) {
+		t.Errorf("CPUPercent() = %v, %v, want 20%% since boot", percents, err)
+	}
+
+	memInfo, err := source.Memory(ctx)
+	if err != nil {
+		t.Fatalf("Memory() вернул ошибку: %v", err)
+	}
+	// used = total - free - buffers - (cached + sreclaimable)
+	if memInfo.Total != 4000000*1024 || memInfo.Used != 2000000*1024 || memInfo.Available != 2500000*1024 ||
+		!almostEqual(memInfo.UsedPercent, 50) || memInfo.SwapFree != 1500000*1024 {
+		t.Errorf("Unexpected memory: %+v", memInfo)
+	}
+
+	avg, err := source.(LoadSource).LoadAverage(ctx)
+	if err != nil || avg.Load1 != 0.52 || avg.Load5 != 0.58 || avg.Load15 != 0.59 {
+		t.Errorf("LoadAverage() = %+v, %v", avg, err)
+	}
+
+	processes, err := source.Processes(ctx)
+	if err != nil || len(processes) != 5 {
+		t.Errorf("Processes() = %d processes, %v", len(processes), err)
+	}
+
+	// PID дерева не относятся к текущей машине
+	if err := source.SendSignal(100, time.Time{}, syscall.SIGTERM); err == nil {
+		t.Error("Expected signals to be refused for a fixture procfs")
+	}
+	priorities := source.(PrioritySource)
+	if err := priorities.SetNice(100, 5); err == nil {
+		t.Error("Expected renice to be refused for a fixture procfs")
+	}
+	if err := priorities.SetIOPriority(100, IOPriority{Class: IOClassIdle}); err == nil {
+		t.Error("Expected ionice to be refused for a fixture procfs")
+	}
+	affinity := source.(AffinitySource)
+	if _, err := affinity.Affinity(100); err == nil {
+		t.Error("Expected CPU affinity to be unavailable for a fixture procfs")
+	}
+	if err := affinity.SetAffinity(100, []int{0}, false); err == nil {
+		t.Error("Expected taskset to be refused for a fixture procfs")
+	}
+}
+
+func TestProcSource_CPUDelta(t *testing.T) {
+	source, err := OpenProcSource(filepath.Join("testdata", "proc", "reuse", "1"), CPUPerCore)
+	if err != nil {
+		t.Fatalf("OpenProcSource() вернул ошибку: %v", err)
+	}
+	if _, err := source.CPUPercent(context.Background()); err != nil {
+		t.Fatalf("CPUPercent() вернул ошибку: %v", err)
+	}
+
+	source.(*ProcSource).root = filepath.Join("testdata", "proc", "reuse", "2")
+	percents, err := source.CPUPercent(context.Background())
+	if err != nil {
+		t.Fatalf("CPUPercent() вернул ошибку: %v", err)
+	}
+	// cpu0: +200 тиков, из них 100 простоя; cpu1 не изменился
+	if len(percents) != 2 || !almostEqual(percents[0], 50) || percents[1] != 0 {
+		t.Errorf("Expected [50 0] over the interval, got %v", percents)
+	}
+
+	// Счетчики ушли назад: замер становится новой точкой отсчета
+	source.(*ProcSource).root = filepath.Join("testdata", "proc", "reuse", "1")
+	percents, err = source.CPUPercent(context.Background())
+	if err != nil {
+		t.Fatalf("CPUPercent() вернул ошибку: %v", err)
+	}
+	if len(percents) != 2 || percents[0] != 0 || percents[1] != 0 {
+		t.Errorf("Expected [0 0] after counters went backwards, got %v", percents)
+	}
+	source.(*ProcSource).root = filepath.Join("testdata", "proc", "reuse", "2")
+	if percents, _ = source.CPUPercent(context.Background()); !almostEqual(percents[0], 50) {
+		t.Errorf("Expected the next interval to be measured from the new baseline, got %v", percents)
+	}
+}
+
+func TestOpenProcSource_Missing(t *testing.T) {
+	if _, err := OpenProcSource(filepath.Join("testdata", "proc", "missing"), CPUPerCore); err == nil {
+		t.Error("Expected error for missing procfs root")
+	}
+}
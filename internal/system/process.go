@@ -1,47 +1,120 @@
 package system
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/shirou/gopsutil/v3/process"
 )
 
 // ProcessInfo содержит информацию о процессе
 type ProcessInfo struct {
-	PID     int32
-	Name    string
-	CPU     float64
-	Memory  float32
-	Status  string
+	PID        int32
+	PPID       int32 // PID родительского процесса, 0 если родитель неизвестен
+	Name       string
+	CPU        float64
+	Memory     float32
+	Status     string
+	Cmdline    string // полная командная строка, может быть пустой для потоков ядра
+	User       string // владелец процесса (реальный UID)
+	RSS        uint64 // резидентная память в байтах
+	VSZ        uint64 // виртуальная память в байтах
+	Threads    int32
+	Nice       int32
+	Priority   int32
+	IOPriority IOPriority // класс ввода-вывода (только Linux)
+	StartTime  time.Time
+	CPUTime    time.Duration // накопленное время user + system
+	ReadBytes  uint64        // прочитано с диска за время жизни процесса
+	WriteBytes uint64        // записано на диск за время жизни процесса
+	Cgroup     string        // путь cgroup (только Linux)
 }
 
+// defaultSampler хранит замеры CPU между вызовами GetProcessList
+var defaultSampler = NewCPUSampler(CPUPerCore)
+
 // GetProcessList возвращает список процессов с их характеристиками
 func GetProcessList() ([]ProcessInfo, error) {
-	processes, err := process.Processes()
+	return GetProcessListWithSampler(defaultSampler)
+}
+
+// GetProcessListWithSampler возвращает список процессов, вычисляя загрузку CPU
+// с помощью указанного sampler за интервал с предыдущего вызова
+func GetProcessListWithSampler(sampler *CPUSampler) ([]ProcessInfo, error) {
+	return GetProcessListWithContext(context.Background(), sampler)
+}
+
+// GetProcessListWithContext работает как GetProcessListWithSampler, но
+// прерывает обход процессов при отмене ctx
+func GetProcessListWithContext(ctx context.Context, sampler *CPUSampler) ([]ProcessInfo, error) {
+	processes, err := process.ProcessesWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
 	var processList []ProcessInfo
+	var cpuTimes []CPUTime
 	for _, p := range processes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		name, err := p.Name()
 		if err != nil {
 			continue
 		}
 
-		cpu, _ := p.CPUPercent()
+		cpuTime := CPUTime{PID: p.Pid}
+		cpuTime.CreateTime, _ = p.CreateTime()
+		if times, err := p.Times(); err == nil {
+			cpuTime.Seconds = times.User + times.System
+		}
+		cpuTimes = append(cpuTimes, cpuTime)
+
 		mem, _ := p.MemoryPercent()
 		status, _ := p.Status()
-		
+		cmdline, _ := p.Cmdline()
+		ppid, _ := p.Ppid()
+		threads, _ := p.NumThreads()
+		priority, nice, _ := readPriority(p.Pid)
+
 		processInfo := ProcessInfo{
-			PID:     p.Pid,
-			Name:    name,
-			CPU:     cpu,
-			Memory:  mem,
-			Status:  strings.Join(status, ","),
+			PID:      p.Pid,
+			PPID:     ppid,
+			Name:     name,
+			Memory:   mem,
+			Status:   strings.Join(status, ","),
+			Cmdline:  cmdline,
+			Threads:  threads,
+			Nice:     nice,
+			Priority: priority,
+			CPUTime:  time.Duration(cpuTime.Seconds * float64(time.Second)),
+		}
+		if cpuTime.CreateTime > 0 {
+			processInfo.StartTime = time.UnixMilli(cpuTime.CreateTime)
+		}
+		if uids, err := p.Uids(); err == nil && len(uids) > 0 {
+			processInfo.User = lookupUser(uids[0])
+		}
+		if memInfo, err := p.MemoryInfo(); err == nil {
+			processInfo.RSS = memInfo.RSS
+			processInfo.VSZ = memInfo.VMS
 		}
+		// Счетчики ввода-вывода чужих процессов доступны только root
+		if io, err := p.IOCounters(); err == nil {
+			processInfo.ReadBytes = io.ReadBytes
+			processInfo.WriteBytes = io.WriteBytes
+		}
+		processInfo.IOPriority, _ = readIOPriority(p.Pid)
+		processInfo.Cgroup, _ = readCgroup(p.Pid)
 		processList = append(processList, processInfo)
 	}
 
+	percents := sampler.Sample(now, cpuTimes)
+	for i := range processList {
+		processList[i].CPU = percents[processList[i].PID]
+	}
+
 	return processList, nil
-} 
\ No newline at end of file
+}
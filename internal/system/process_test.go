@@ -2,6 +2,7 @@ package system
 
 import (
 	"testing"
+	"time"
 )
 
 func TestGetProcessList(t *testing.T) {
@@ -24,6 +25,11 @@ func TestGetProcessList(t *testing.T) {
 			t.Errorf("Процесс имеет некорректный PID: %d", p.PID)
 		}
 
+		// PPID не может быть отрицательным
+		if p.PPID < 0 {
+			t.Errorf("Процесс с PID %d имеет некорректный PPID: %d", p.PID, p.PPID)
+		}
+
 		// Имя процесса не должно быть пустым
 		if p.Name == "" {
 			t.Errorf("Процесс с PID %d имеет пустое имя", p.PID)
@@ -35,6 +41,12 @@ func TestGetProcessList(t *testing.T) {
 				p.Name, p.PID, p.CPU)
 		}
 
+		// Процесс не может быть запущен в будущем
+		if p.StartTime.After(time.Now()) {
+			t.Errorf("Процесс %s (PID: %d) имеет время запуска в будущем: %v",
+				p.Name, p.PID, p.StartTime)
+		}
+
 		// Memory не может быть отрицательным или больше 100%
 		if p.Memory < 0 || p.Memory > 100 {
 			t.Errorf("Процесс %s (PID: %d) имеет некорректное значение Memory: %f", 
@@ -1,8 +1,14 @@
 package system
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/shirou/gopsutil/v3/process"
 )
@@ -12,28 +18,158 @@ type Signal struct {
 	Name        string
 	Description string
 	Signal      syscall.Signal
+	Action      SignalAction // Действие по умолчанию, если процесс не обрабатывает сигнал
 }
 
-// AvailableSignals возвращает список доступных сигналов
+// SignalAction - действие по умолчанию при получении сигнала, см. signal(7)
+type SignalAction int
+
+// Действия по умолчанию
+const (
+	ActionTerminate SignalAction = iota // завершить процесс
+	ActionCore                          // завершить процесс с дампом памяти
+	ActionIgnore                        // игнорировать
+	ActionStop                          // остановить процесс
+	ActionContinue                      // продолжить остановленный процесс
+)
+
+// String возвращает краткое название действия, как в signal(7)
+func (a SignalAction) String() string {
+	switch a {
+	case ActionCore:
+		return "Core"
+	case ActionIgnore:
+		return "Ign"
+	case ActionStop:
+		return "Stop"
+	case ActionContinue:
+		return "Cont"
+	}
+	return "Term"
+}
+
+// Number возвращает номер сигнала
+func (s Signal) Number() int {
+	return int(s.Signal)
+}
+
+// AvailableSignals - все сигналы платформы по возрастанию номера
 var AvailableSignals = getAvailableSignals()
 
-// getAvailableSignals возвращает список сигналов в зависимости от ОС
+// getAvailableSignals возвращает сигналы POSIX и сигналы, которые есть только
+// на текущей ОС, по возрастанию номера
 func getAvailableSignals() []Signal {
-	return []Signal{
-		{Name: "SIGTERM", Description: "Terminate process", Signal: syscall.SIGTERM},
-		{Name: "SIGKILL", Description: "Kill process", Signal: syscall.SIGKILL},
-		{Name: "SIGINT", Description: "Interrupt process", Signal: syscall.SIGINT},
-		{Name: "SIGHUP", Description: "Hangup", Signal: syscall.SIGHUP},
-		{Name: "SIGQUIT", Description: "Quit", Signal: syscall.SIGQUIT},
-		{Name: "SIGABRT", Description: "Abort", Signal: syscall.SIGABRT},
-		{Name: "SIGUSR1", Description: "User-defined signal 1", Signal: syscall.SIGUSR1},
-		{Name: "SIGUSR2", Description: "User-defined signal 2", Signal: syscall.SIGUSR2},
-		{Name: "SIGSTOP", Description: "Stop process", Signal: syscall.SIGSTOP},
-		{Name: "SIGCONT", Description: "Continue process", Signal: syscall.SIGCONT},
+	signals := []Signal{
+		{"SIGHUP", "Hangup", syscall.SIGHUP, ActionTerminate},
+		{"SIGINT", "Interrupt", syscall.SIGINT, ActionTerminate},
+		{"SIGQUIT", "Quit", syscall.SIGQUIT, ActionCore},
+		{"SIGILL", "Illegal instruction", syscall.SIGILL, ActionCore},
+		{"SIGTRAP", "Trace/breakpoint trap", syscall.SIGTRAP, ActionCore},
+		{"SIGABRT", "Aborted", syscall.SIGABRT, ActionCore},
+		{"SIGBUS", "Bus error", syscall.SIGBUS, ActionCore},
+		{"SIGFPE", "Floating point exception", syscall.SIGFPE, ActionCore},
+		{"SIGKILL", "Killed", syscall.SIGKILL, ActionTerminate},
+		{"SIGUSR1", "User defined signal 1", syscall.SIGUSR1, ActionTerminate},
+		{"SIGSEGV", "Segmentation fault", syscall.SIGSEGV, ActionCore},
+		{"SIGUSR2", "User defined signal 2", syscall.SIGUSR2, ActionTerminate},
+		{"SIGPIPE", "Broken pipe", syscall.SIGPIPE, ActionTerminate},
+		{"SIGALRM", "Alarm clock", syscall.SIGALRM, ActionTerminate},
+		{"SIGTERM", "Terminated", syscall.SIGTERM, ActionTerminate},
+		{"SIGCHLD", "Child exited", syscall.SIGCHLD, ActionIgnore},
+		{"SIGCONT", "Continued", syscall.SIGCONT, ActionContinue},
+		{"SIGSTOP", "Stopped (signal)", syscall.SIGSTOP, ActionStop},
+		{"SIGTSTP", "Stopped", syscall.SIGTSTP, ActionStop},
+		{"SIGTTIN", "Stopped (tty input)", syscall.SIGTTIN, ActionStop},
+		{"SIGTTOU", "Stopped (tty output)", syscall.SIGTTOU, ActionStop},
+		{"SIGURG", "Urgent I/O condition", syscall.SIGURG, ActionIgnore},
+		{"SIGXCPU", "CPU time limit exceeded", syscall.SIGXCPU, ActionCore},
+		{"SIGXFSZ", "File size limit exceeded", syscall.SIGXFSZ, ActionCore},
+		{"SIGVTALRM", "Virtual timer expired", syscall.SIGVTALRM, ActionTerminate},
+		{"SIGPROF", "Profiling timer expired", syscall.SIGPROF, ActionTerminate},
+		{"SIGWINCH", "Window changed", syscall.SIGWINCH, ActionIgnore},
+		{"SIGIO", "I/O possible", syscall.SIGIO, ActionTerminate},
+		{"SIGSYS", "Bad system call", syscall.SIGSYS, ActionCore},
+	}
+	signals = append(signals, platformSignals()...)
+	sort.Slice(signals, func(i, j int) bool { return signals[i].Signal < signals[j].Signal })
+	return signals
+}
+
+// FindSignal ищет сигнал по имени: "SIGTERM", "term" или "RTMIN+2"
+func FindSignal(name string) (Signal, bool) {
+	name = strings.ToUpper(name)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	for _, sig := range AvailableSignals {
+		if sig.Name == name {
+			return sig, true
+		}
+	}
+	return Signal{}, false
+}
+
+// ErrProcessChanged - процесс завершился, и его PID занял другой процесс
+var ErrProcessChanged = errors.New("process has exited and its PID was reused")
+
+// ErrProtectedProcess - сигнал процессу, которому его без явного подтверждения
+// не отправляют: init или сам htop
+var ErrProtectedProcess = errors.New("protected process")
+
+// Destructive сообщает, что сигнал завершает процесс без возможности его
+// перехватить, поэтому перед отправкой нужно подтверждение
+func (s Signal) Destructive() bool {
+	return s.Signal == syscall.SIGKILL
+}
+
+// CheckSignalTarget возвращает ErrProtectedProcess для init (PID 1) и текущего
+// процесса: сигнал им обычно отправляют по ошибке
+func CheckSignalTarget(pid int32) error {
+	switch {
+	case pid == 1:
+		return fmt.Errorf("refusing to signal init (PID 1): %w", ErrProtectedProcess)
+	case int(pid) == os.Getpid():
+		return fmt.Errorf("refusing to signal htop itself (PID %d): %w", pid, ErrProtectedProcess)
+	}
+	return nil
+}
+
+// signalProcess отправляет сигнал процессу, запущенному в start. Время запуска
+// сверяется непосредственно перед отправкой: PID из списка получен до секунды
+// назад, и за это время процесс мог завершиться, а PID - достаться другому.
+// Нулевое start означает, что время запуска неизвестно и не сверяется.
+func signalProcess(pid int32, start time.Time, sig syscall.Signal, startTime func(pid int32) (time.Time, error)) error {
+	// kill(2) с PID 0 и отрицательным PID отправляет сигнал группе процессов
+	if pid <= 0 {
+		return fmt.Errorf("invalid PID %d", pid)
+	}
+	if !start.IsZero() {
+		current, err := startTime(pid)
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("process %d has exited", pid)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot verify process %d: %w", pid, err)
+		}
+		if !current.Equal(start) {
+			return fmt.Errorf("process %d: %w", pid, ErrProcessChanged)
+		}
+	}
+	return SendSignal(pid, sig)
+}
+
+// processNotRunning приводит ошибку gopsutil о завершенном процессе к
+// fs.ErrNotExist, как у ProcCollector, чтобы signalProcess различал
+// завершенный процесс и отказ в доступе на любой платформе
+func processNotRunning(pid int32, err error) error {
+	if errors.Is(err, process.ErrorProcessNotRunning) {
+		return fmt.Errorf("process %d: %w", pid, fs.ErrNotExist)
 	}
+	return err
 }
 
-// SendSignal отправляет сигнал процессу
+// SendSignal отправляет сигнал процессу без проверки, что PID принадлежит
+// тому же процессу
 func SendSignal(pid int32, sig syscall.Signal) error {
 	proc, err := process.NewProcess(pid)
 	if err != nil {
@@ -0,0 +1,36 @@
+package system
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Границы сигналов реального времени, доступных программам. Ядро нумерует их
+// с 32, но glibc и musl занимают первые для своих нужд, поэтому SIGRTMIN
+// в программах, как и в kill -l, равен 34.
+const (
+	sigRTMin = 34
+	sigRTMax = 64
+)
+
+// platformSignals возвращает сигналы, которые есть только в Linux: SIGSTKFLT,
+// SIGPWR и сигналы реального времени, названные как в kill -l
+func platformSignals() []Signal {
+	signals := []Signal{
+		{"SIGSTKFLT", "Stack fault", syscall.SIGSTKFLT, ActionTerminate},
+		{"SIGPWR", "Power failure", syscall.SIGPWR, ActionTerminate},
+	}
+	for n := sigRTMin; n <= sigRTMax; n++ {
+		name := fmt.Sprintf("SIGRTMIN+%d", n-sigRTMin)
+		switch {
+		case n == sigRTMin:
+			name = "SIGRTMIN"
+		case n == sigRTMax:
+			name = "SIGRTMAX"
+		case n > (sigRTMin+sigRTMax)/2:
+			name = fmt.Sprintf("SIGRTMAX-%d", sigRTMax-n)
+		}
+		signals = append(signals, Signal{name, fmt.Sprintf("Real-time signal %d", n-sigRTMin), syscall.Signal(n), ActionTerminate})
+	}
+	return signals
+}
@@ -0,0 +1,38 @@
+package system
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestPlatformSignals_Realtime(t *testing.T) {
+	// Имена совпадают с kill -l: первая половина от SIGRTMIN, вторая - от SIGRTMAX
+	for name, want := range map[string]int{
+		"SIGRTMIN":    34,
+		"SIGRTMIN+1":  35,
+		"SIGRTMIN+15": 49,
+		"SIGRTMAX-14": 50,
+		"SIGRTMAX-1":  63,
+		"SIGRTMAX":    64,
+		"SIGSTKFLT":   16,
+		"SIGPWR":      30,
+	} {
+		sig, ok := FindSignal(name)
+		if !ok || sig.Number() != want {
+			t.Errorf("FindSignal(%q) = %d, %v, want %d", name, sig.Number(), ok, want)
+		}
+	}
+
+	count := 0
+	for _, sig := range AvailableSignals {
+		if sig.Signal >= sigRTMin {
+			count++
+		}
+	}
+	if count != sigRTMax-sigRTMin+1 {
+		t.Errorf("Expected %d real-time signals, got %d", sigRTMax-sigRTMin+1, count)
+	}
+	if sig, _ := FindSignal("RTMIN"); sig.Signal != syscall.Signal(sigRTMin) {
+		t.Errorf("Expected RTMIN to resolve to %d, got %d", sigRTMin, sig.Signal)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package system
+
+// platformSignals возвращает сигналы, которые есть только на текущей ОС.
+// Вне Linux меню ограничено сигналами POSIX.
+func platformSignals() []Signal {
+	return nil
+}
@@ -3,34 +3,51 @@
 package system
 
 import (
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
 	"syscall"
 	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
 )
 
 func TestAvailableSignals(t *testing.T) {
 	signals := getAvailableSignals()
 
-	// Проверяем сигналы для Linux/Unix
+	// Проверяем сигналы для Linux/Unix, включая нужные демонам для перезагрузки и ротации логов
 	expectedSignals := map[syscall.Signal]string{
-		syscall.SIGTERM: "SIGTERM",
-		syscall.SIGKILL: "SIGKILL",
-		syscall.SIGINT:  "SIGINT",
-		syscall.SIGHUP:  "SIGHUP",
-		syscall.SIGQUIT: "SIGQUIT",
-		syscall.SIGABRT: "SIGABRT",
-		syscall.SIGUSR1: "SIGUSR1",
-		syscall.SIGUSR2: "SIGUSR2",
-		syscall.SIGSTOP: "SIGSTOP",
-		syscall.SIGCONT: "SIGCONT",
+		syscall.SIGTERM:  "SIGTERM",
+		syscall.SIGKILL:  "SIGKILL",
+		syscall.SIGINT:   "SIGINT",
+		syscall.SIGHUP:   "SIGHUP",
+		syscall.SIGQUIT:  "SIGQUIT",
+		syscall.SIGABRT:  "SIGABRT",
+		syscall.SIGUSR1:  "SIGUSR1",
+		syscall.SIGUSR2:  "SIGUSR2",
+		syscall.SIGSTOP:  "SIGSTOP",
+		syscall.SIGCONT:  "SIGCONT",
+		syscall.SIGWINCH: "SIGWINCH",
+		syscall.SIGTSTP:  "SIGTSTP",
+		syscall.SIGPIPE:  "SIGPIPE",
 	}
 
-	if len(signals) != len(expectedSignals) {
-		t.Errorf("Expected %d signals for Unix, got %d", len(expectedSignals), len(signals))
+	found := make(map[syscall.Signal]bool)
+	for i, sig := range signals {
+		if i > 0 && sig.Signal <= signals[i-1].Signal {
+			t.Errorf("Signals are not sorted by number: %s after %s", sig.Name, signals[i-1].Name)
+		}
+		if name, ok := expectedSignals[sig.Signal]; ok && name != sig.Name {
+			t.Errorf("Signal %d is named %s, want %s", sig.Number(), sig.Name, name)
+		}
+		found[sig.Signal] = true
 	}
-
-	for _, sig := range signals {
-		if _, ok := expectedSignals[sig.Signal]; !ok {
-			t.Errorf("Unexpected signal %s in Unix signals list", sig.Name)
+	for sig, name := range expectedSignals {
+		if !found[sig] {
+			t.Errorf("Signal %s is missing", name)
 		}
 	}
 
@@ -42,10 +59,131 @@ func TestAvailableSignals(t *testing.T) {
 	}
 }
 
+func TestSignalActions(t *testing.T) {
+	for name, want := range map[string]SignalAction{
+		"SIGTERM":  ActionTerminate,
+		"SIGQUIT":  ActionCore,
+		"SIGWINCH": ActionIgnore,
+		"SIGTSTP":  ActionStop,
+		"SIGCONT":  ActionContinue,
+	} {
+		sig, ok := FindSignal(name)
+		if !ok || sig.Action != want {
+			t.Errorf("FindSignal(%q) = %+v, %v, want action %v", name, sig, ok, want)
+		}
+	}
+	if ActionCore.String() != "Core" || ActionTerminate.String() != "Term" {
+		t.Errorf("Unexpected action names %q, %q", ActionCore, ActionTerminate)
+	}
+}
+
+func TestFindSignal(t *testing.T) {
+	for _, name := range []string{"SIGTERM", "term", "sigTerm"} {
+		if sig, ok := FindSignal(name); !ok || sig.Signal != syscall.SIGTERM {
+			t.Errorf("FindSignal(%q) = %+v, %v", name, sig, ok)
+		}
+	}
+	if _, ok := FindSignal("SIGNOPE"); ok {
+		t.Error("Expected unknown signal not to be found")
+	}
+}
+
 func TestSendSignal(t *testing.T) {
 	// Тест на отправку сигнала несуществующему процессу
 	err := SendSignal(-1, syscall.SIGTERM)
 	if err == nil {
 		t.Error("Expected error when sending signal to non-existent process, got nil")
 	}
-} 
\ No newline at end of file
+}
+
+func TestCheckSignalTarget(t *testing.T) {
+	for _, pid := range []int32{1, int32(os.Getpid())} {
+		if err := CheckSignalTarget(pid); !errors.Is(err, ErrProtectedProcess) {
+			t.Errorf("Expected PID %d to be protected, got %v", pid, err)
+		}
+	}
+	if err := CheckSignalTarget(int32(os.Getppid())); err != nil && os.Getppid() != 1 {
+		t.Errorf("Expected the parent process not to be protected, got %v", err)
+	}
+}
+
+func TestSignalDestructive(t *testing.T) {
+	for _, sig := range getAvailableSignals() {
+		if want := sig.Signal == syscall.SIGKILL; sig.Destructive() != want {
+			t.Errorf("%s.Destructive() = %v, want %v", sig.Name, sig.Destructive(), want)
+		}
+	}
+}
+
+func TestSignalProcess_Identity(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	startTime := func(pid int32) (time.Time, error) {
+		if pid == 2 {
+			return time.Time{}, fs.ErrNotExist
+		}
+		return start, nil
+	}
+
+	if err := signalProcess(0, start, syscall.SIGTERM, startTime); err == nil {
+		t.Error("Expected PID 0 to be rejected: kill(0) signals the process group")
+	}
+	if err := signalProcess(-1, time.Time{}, syscall.SIGTERM, startTime); err == nil {
+		t.Error("Expected PID -1 to be rejected: kill(-1) signals every process")
+	}
+	err := signalProcess(42, start.Add(time.Second), syscall.SIGTERM, startTime)
+	if !errors.Is(err, ErrProcessChanged) {
+		t.Errorf("Expected ErrProcessChanged for another start time, got %v", err)
+	}
+	if err := signalProcess(2, start, syscall.SIGTERM, startTime); err == nil || !strings.Contains(err.Error(), "exited") {
+		t.Errorf("Expected exited process error, got %v", err)
+	}
+}
+
+func TestProcessNotRunning(t *testing.T) {
+	err := processNotRunning(42, process.ErrorProcessNotRunning)
+	if !errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), "42") {
+		t.Errorf("Expected ErrNotExist for a process that is not running, got %v", err)
+	}
+	// Время запуска из gopsutil дает то же сообщение, что и из /proc
+	startTime := func(pid int32) (time.Time, error) {
+		return time.Time{}, processNotRunning(pid, process.ErrorProcessNotRunning)
+	}
+	if err := signalProcess(42, time.Now(), syscall.SIGTERM, startTime); err == nil || !strings.Contains(err.Error(), "exited") {
+		t.Errorf("Expected exited process error, got %v", err)
+	}
+
+	if err := processNotRunning(42, fs.ErrPermission); err != fs.ErrPermission {
+		t.Errorf("Expected other errors to pass through, got %v", err)
+	}
+}
+
+func TestLiveSource_SendSignalVerifiesStartTime(t *testing.T) {
+	cmd := exec.Command("sleep", "10")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	defer cmd.Process.Kill()
+	pid := int32(cmd.Process.Pid)
+
+	source := NewLiveSource(CPUPerCore)
+	start, err := source.processes.StartTime(pid)
+	if err != nil {
+		t.Fatalf("StartTime() вернул ошибку: %v", err)
+	}
+
+	// Время запуска другого процесса - сигнал не отправляется
+	err = source.SendSignal(pid, start.Add(-time.Hour), syscall.SIGTERM)
+	if !errors.Is(err, ErrProcessChanged) {
+		t.Fatalf("Expected ErrProcessChanged, got %v", err)
+	}
+	if err := cmd.Process.Signal(syscall.Signal(0)); err != nil {
+		t.Fatalf("Expected the process to stay alive, got %v", err)
+	}
+
+	if err := source.SendSignal(pid, start, syscall.SIGTERM); err != nil {
+		t.Fatalf("SendSignal() вернул ошибку: %v", err)
+	}
+	if err := cmd.Wait(); err == nil {
+		t.Error("Expected the process to be terminated by SIGTERM")
+	}
+}
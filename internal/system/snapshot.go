@@ -0,0 +1,80 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// Snapshot - состояние системы на момент одного замера. После отправки
+// снимок не изменяется ни отправителем, ни получателем: получатель, которому
+// нужно пересортировать процессы, работает с копией.
+type Snapshot struct {
+	Time       time.Time
+	CPU        []float64 // загрузка каждого ядра в процентах
+	Memory     *mem.VirtualMemoryStat
+	Load       *load.AvgStat // средняя загрузка, nil - источник ее не сообщает
+	Processes  []ProcessInfo
+	ProcessErr error // ошибка получения списка процессов, остальные данные актуальны
+	Err        error // ошибка получения CPU или памяти, снимок пуст
+}
+
+// CollectFunc собирает один снимок
+type CollectFunc func(ctx context.Context) Snapshot
+
+// Collector возвращает CollectFunc, собирающую снимок из source
+func Collector(source Source) CollectFunc {
+	return func(ctx context.Context) Snapshot {
+		cpuPercents, err := source.CPUPercent(ctx)
+		if err != nil {
+			return Snapshot{Err: fmt.Errorf("failed to get CPU percent: %v", err)}
+		}
+		memInfo, err := source.Memory(ctx)
+		if err != nil {
+			return Snapshot{Err: fmt.Errorf("failed to get memory info: %v", err)}
+		}
+		s := Snapshot{Time: time.Now(), CPU: cpuPercents, Memory: memInfo}
+		// Средняя загрузка необязательна: при ошибке снимок остается без нее
+		if loadSource, ok := source.(LoadSource); ok {
+			s.Load, _ = loadSource.LoadAverage(ctx)
+		}
+		s.Processes, s.ProcessErr = source.Processes(ctx)
+		return s
+	}
+}
+
+// StartSampler запускает горутину, которая собирает снимок сразу и затем
+// каждые interval. В канале хранится только последний снимок: если получатель
+// не успел забрать предыдущий, тот отбрасывается. Канал закрывается после
+// отмены ctx.
+func StartSampler(ctx context.Context, interval time.Duration, collect CollectFunc) <-chan Snapshot {
+	out := make(chan Snapshot, 1)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			s := collect(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			// Отправитель один, поэтому после удаления устаревшего снимка
+			// в буфере гарантированно есть место
+			select {
+			case <-out:
+			default:
+			}
+			out <- s
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return out
+}
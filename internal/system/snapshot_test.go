@@ -0,0 +1,85 @@
+package system
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStartSampler_FirstSnapshotImmediately(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	collect := func(ctx context.Context) Snapshot {
+		return Snapshot{Processes: []ProcessInfo{{PID: 1}}}
+	}
+	snapshots := StartSampler(ctx, time.Hour, collect)
+
+	select {
+	case s := <-snapshots:
+		if len(s.Processes) != 1 {
+			t.Errorf("Unexpected snapshot: %+v", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected first snapshot without waiting for the interval")
+	}
+}
+
+func TestStartSampler_KeepsOnlyLatest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var count atomic.Int32
+	collect := func(ctx context.Context) Snapshot {
+		n := count.Add(1)
+		return Snapshot{Processes: []ProcessInfo{{PID: n}}}
+	}
+	snapshots := StartSampler(ctx, 5*time.Millisecond, collect)
+
+	// Пока получатель занят, устаревшие снимки отбрасываются
+	time.Sleep(100 * time.Millisecond)
+	s := <-snapshots
+	if got := s.Processes[0].PID; got < 5 {
+		t.Errorf("Expected a recent snapshot, got #%d of %d", got, count.Load())
+	}
+}
+
+func TestStartSampler_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	started := make(chan struct{})
+	collect := func(ctx context.Context) Snapshot {
+		close(started)
+		<-ctx.Done() // медленный сбор, прерываемый отменой
+		return Snapshot{Err: ctx.Err()}
+	}
+	snapshots := StartSampler(ctx, time.Hour, collect)
+	<-started
+	cancel()
+
+	select {
+	case s, ok := <-snapshots:
+		if ok {
+			t.Errorf("Expected channel to be closed without a snapshot, got %+v", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Sampler did not stop after cancel")
+	}
+}
+
+func TestCollector(t *testing.T) {
+	s := Collector(NewLiveSource(CPUPerCore))(context.Background())
+	if s.Err != nil {
+		t.Fatalf("Collector вернул ошибку: %v", s.Err)
+	}
+	if s.Time.IsZero() || len(s.CPU) == 0 || s.Memory == nil || len(s.Processes) == 0 {
+		t.Errorf("Expected a complete snapshot, got CPU=%v memory=%v processes=%d", s.CPU, s.Memory, len(s.Processes))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GetProcessListWithContext(ctx, NewCPUSampler(CPUPerCore)); err == nil {
+		t.Error("Expected error for cancelled context")
+	}
+}
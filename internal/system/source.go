@@ -0,0 +1,84 @@
+package system
+
+import (
+	"context"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// Source - источник метрик системы и операций над процессами. Интерфейс
+// работает только через Source, поэтому данные могут поступать не только
+// с текущей машины, но и из записи, по сети или из теста.
+type Source interface {
+	// CPUCount возвращает количество логических ядер
+	CPUCount() (int, error)
+	// CPUPercent возвращает загрузку каждого ядра в процентах с предыдущего вызова
+	CPUPercent(ctx context.Context) ([]float64, error)
+	// Memory возвращает данные об оперативной памяти
+	Memory(ctx context.Context) (*mem.VirtualMemoryStat, error)
+	// Processes возвращает список процессов с загрузкой CPU с предыдущего вызова
+	Processes(ctx context.Context) ([]ProcessInfo, error)
+	// SendSignal отправляет сигнал процессу, запущенному в start. Если PID уже
+	// занят процессом, запущенным в другое время, сигнал не отправляется.
+	SendSignal(pid int32, start time.Time, sig syscall.Signal) error
+}
+
+// LoadSource - Source, сообщающий среднюю загрузку системы. Источники, у которых
+// ее нет (запись сеанса, тесты), этот интерфейс не реализуют.
+type LoadSource interface {
+	// LoadAverage возвращает среднюю загрузку за 1, 5 и 15 минут
+	LoadAverage(ctx context.Context) (*load.AvgStat, error)
+}
+
+// processCollector - способ получения списка процессов LiveSource
+type processCollector interface {
+	// Processes возвращает список процессов
+	Processes(ctx context.Context) ([]ProcessInfo, error)
+	// StartTime возвращает время запуска процесса так, как оно указано в списке
+	StartTime(pid int32) (time.Time, error)
+}
+
+// LiveSource - Source текущей машины. Метрики системы собираются через gopsutil,
+// список процессов в Linux - через ProcCollector.
+type LiveSource struct {
+	processes processCollector
+}
+
+// NewLiveSource создает LiveSource, нормирующий загрузку CPU процессов в режиме mode
+func NewLiveSource(mode CPUMode) *LiveSource {
+	return &LiveSource{processes: newProcessCollector(NewCPUSampler(mode))}
+}
+
+// CPUCount возвращает количество логических ядер
+func (s *LiveSource) CPUCount() (int, error) {
+	return cpu.Counts(true)
+}
+
+// CPUPercent возвращает загрузку каждого ядра
+func (s *LiveSource) CPUPercent(ctx context.Context) ([]float64, error) {
+	return cpu.PercentWithContext(ctx, 0, true)
+}
+
+// Memory возвращает данные об оперативной памяти
+func (s *LiveSource) Memory(ctx context.Context) (*mem.VirtualMemoryStat, error) {
+	return mem.VirtualMemoryWithContext(ctx)
+}
+
+// LoadAverage возвращает среднюю загрузку системы
+func (s *LiveSource) LoadAverage(ctx context.Context) (*load.AvgStat, error) {
+	return load.AvgWithContext(ctx)
+}
+
+// Processes возвращает список процессов
+func (s *LiveSource) Processes(ctx context.Context) ([]ProcessInfo, error) {
+	return s.processes.Processes(ctx)
+}
+
+// SendSignal отправляет сигнал процессу, убедившись, что PID не занят другим процессом
+func (s *LiveSource) SendSignal(pid int32, start time.Time, sig syscall.Signal) error {
+	return signalProcess(pid, start, sig, s.processes.StartTime)
+}
@@ -0,0 +1,42 @@
+package system
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestLiveSource(t *testing.T) {
+	var source Source = NewLiveSource(CPUPerCore)
+	ctx := context.Background()
+
+	count, err := source.CPUCount()
+	if err != nil || count <= 0 {
+		t.Fatalf("CPUCount() = %d, %v", count, err)
+	}
+	percents, err := source.CPUPercent(ctx)
+	if err != nil || len(percents) != count {
+		t.Errorf("Expected %d CPU values, got %v, %v", count, percents, err)
+	}
+	memInfo, err := source.Memory(ctx)
+	if err != nil || memInfo.Total == 0 {
+		t.Errorf("Unexpected memory info: %+v, %v", memInfo, err)
+	}
+
+	if _, err := source.(LoadSource).LoadAverage(ctx); err != nil {
+		t.Errorf("LoadAverage() вернул ошибку: %v", err)
+	}
+
+	processes, err := source.Processes(ctx)
+	if err != nil {
+		t.Fatalf("Processes() вернул ошибку: %v", err)
+	}
+	self := int32(os.Getpid())
+	found := false
+	for _, p := range processes {
+		found = found || p.PID == self
+	}
+	if !found {
+		t.Errorf("Expected own process %d in the list", self)
+	}
+}
@@ -0,0 +1,30 @@
+package system
+
+import (
+	"os/user"
+	"strconv"
+	"sync"
+)
+
+// userCache кэширует имена пользователей по UID, чтобы не обращаться
+// к базе пользователей для каждого процесса на каждом тике
+var userCache = struct {
+	sync.Mutex
+	names map[int32]string
+}{names: make(map[int32]string)}
+
+// lookupUser возвращает имя пользователя по UID или сам UID, если имя неизвестно
+func lookupUser(uid int32) string {
+	userCache.Lock()
+	defer userCache.Unlock()
+
+	if name, ok := userCache.names[uid]; ok {
+		return name
+	}
+	name := strconv.Itoa(int(uid))
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	userCache.names[uid] = name
+	return name
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// affinityMenuWidth - ширина диалога привязки к ядрам
+const affinityMenuWidth = 48
+
+// errNoAffinity - источник данных не умеет работать с привязкой к ядрам
+var errNoAffinity = errors.New("CPU affinity is not supported for this data source")
+
+// affinityDialog - диалог привязки процесса к ядрам: строка с флажком на каждое
+// ядро и последняя строка - применить ли привязку ко всем потокам
+type affinityDialog struct {
+	threads    int32  // потоков выбранного процесса, 0 - действие над отмеченными
+	cpus       []bool // выбранные ядра по номеру
+	allThreads bool
+	targets    actionTargets // процессы на момент открытия диалога
+	list       *widgets.List
+}
+
+// openAffinity показывает диалог привязки к ядрам для выбранного процесса,
+// отмечая ядра, к которым он привязан сейчас
+func (d *Dashboard) openAffinity() {
+	p, ok := d.selectedProcess()
+	if !ok {
+		return
+	}
+	source, ok := d.source.(system.AffinitySource)
+	if !ok {
+		d.report(severityError, "Failed to read CPU affinity of process %d: %v", p.PID, errNoAffinity)
+		return
+	}
+	current, err := source.Affinity(p.PID)
+	if err != nil {
+		d.report(severityError, "Failed to read CPU affinity of process %d: %v", p.PID, err)
+		return
+	}
+
+	// Ядра перечисляются по количеству индикаторов CPU; если процесс привязан
+	// к ядру с большим номером, список расширяется, чтобы привязка не потерялась
+	cores := len(d.cpuCharts)
+	for _, cpu := range current {
+		cores = max(cores, cpu+1)
+	}
+	dialog := &affinityDialog{
+		threads: p.Threads,
+		cpus:    make([]bool, cores),
+		targets: d.targets(),
+		list:    widgets.NewList(),
+	}
+	for _, cpu := range current {
+		dialog.cpus[cpu] = true
+	}
+	dialog.list.Title = fmt.Sprintf("Affinity of %d (Space toggle, a all, Enter apply)", p.PID)
+	if dialog.targets.tagged {
+		// Привязка выбранного процесса - отправная точка для всех отмеченных
+		dialog.list.Title = fmt.Sprintf("Affinity of %d tagged (Space, a all, Enter apply)", len(dialog.targets.processes))
+		dialog.threads = 0
+	}
+	dialog.list.TextStyle = ui.NewStyle(ui.ColorWhite)
+	dialog.list.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorYellow)
+	dialog.list.WrapText = false
+	if d.options.Monochrome {
+		plain := ui.NewStyle(ui.ColorClear)
+		dialog.list.BorderStyle = plain
+		dialog.list.TitleStyle = plain
+		dialog.list.TextStyle = plain
+		dialog.list.SelectedRowStyle = ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierReverse)
+	}
+	d.affinity = dialog
+	d.updateAffinityRows()
+}
+
+// handleAffinityKey обрабатывает клавиши в диалоге привязки к ядрам
+func (d *Dashboard) handleAffinityKey(id string) {
+	dialog := d.affinity
+	switch id {
+	case "<Left>", "<Escape>":
+		d.affinity = nil
+		return
+	case "<Up>":
+		dialog.list.ScrollUp()
+	case "<Down>":
+		dialog.list.ScrollDown()
+	case "<Space>":
+		if row := dialog.list.SelectedRow; row < len(dialog.cpus) {
+			dialog.cpus[row] = !dialog.cpus[row]
+		} else {
+			dialog.allThreads = !dialog.allThreads
+		}
+	case "a":
+		// Если выбраны все ядра, снимаем выбор, иначе выбираем все
+		all := !dialog.allSelected()
+		for i := range dialog.cpus {
+			dialog.cpus[i] = all
+		}
+	case "<Enter>":
+		d.affinity = nil
+		d.applyAffinity(dialog)
+		return
+	}
+	d.updateAffinityRows()
+}
+
+// allSelected сообщает, выбраны ли все ядра
+func (dialog *affinityDialog) allSelected() bool {
+	for _, selected := range dialog.cpus {
+		if !selected {
+			return false
+		}
+	}
+	return true
+}
+
+// applyAffinity привязывает выбранный или отмеченные процессы к выбранным
+// в диалоге ядрам
+func (d *Dashboard) applyAffinity(dialog *affinityDialog) {
+	var cpus []int
+	for cpu, selected := range dialog.cpus {
+		if selected {
+			cpus = append(cpus, cpu)
+		}
+	}
+	source, supported := d.source.(system.AffinitySource)
+	d.runAction("set CPU affinity of", dialog.targets, func(p system.ProcessInfo) error {
+		if !supported {
+			return errNoAffinity
+		}
+		return source.SetAffinity(p.PID, cpus, dialog.allThreads)
+	})
+}
+
+// updateAffinityRows перестраивает строки диалога по выбранным ядрам
+func (d *Dashboard) updateAffinityRows() {
+	dialog := d.affinity
+	check := func(checked bool) string {
+		if checked {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	rows := make([]string, 0, len(dialog.cpus)+1)
+	for cpu, selected := range dialog.cpus {
+		rows = append(rows, fmt.Sprintf("%s CPU %d", check(selected), cpu))
+	}
+	threads := "Apply to all threads"
+	if dialog.threads > 0 {
+		threads = fmt.Sprintf("Apply to all %d threads", dialog.threads)
+	}
+	rows = append(rows, check(dialog.allThreads)+" "+threads)
+	dialog.list.Rows = rows
+}
+
+// updateAffinityPosition располагает диалог привязки к ядрам у курсора
+func (d *Dashboard) updateAffinityPosition() {
+	rect := d.menuRect(affinityMenuWidth, len(d.affinity.list.Rows))
+	d.affinity.list.SetRect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y)
+}
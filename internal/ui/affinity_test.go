@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDashboard_AffinityDialog(t *testing.T) {
+	source := newFakeSource()
+	source.processes[1].Threads = 4
+	source.affinity = map[int32][]int{200: {1, 2}}
+	dashboard := newPriorityDashboard(t, source)
+
+	dashboard.handleKey("a")
+	if dashboard.affinity == nil {
+		t.Fatal("Expected a to open the affinity dialog")
+	}
+	want := []string{"[ ] CPU 0", "[x] CPU 1", "[x] CPU 2", "[ ] Apply to all 4 threads"}
+	if !slices.Equal(dashboard.affinity.list.Rows, want) {
+		t.Errorf("Expected a checkbox per core, got %q", dashboard.affinity.list.Rows)
+	}
+
+	// Добавляем ядро 0, убираем ядро 2 и включаем применение ко всем потокам
+	dashboard.handleKey("<Space>")
+	dashboard.handleKey("<Down>")
+	dashboard.handleKey("<Down>")
+	dashboard.handleKey("<Space>")
+	dashboard.handleKey("<Down>")
+	dashboard.handleKey("<Space>")
+	dashboard.handleKey("<Enter>")
+	if dashboard.affinity != nil {
+		t.Error("Expected Enter to close the dialog")
+	}
+	if !slices.Equal(source.affinity[200], []int{0, 1}) || !source.affinityThreads {
+		t.Errorf("Expected CPUs 0-1 for all threads, got %v (threads %v)", source.affinity[200], source.affinityThreads)
+	}
+}
+
+func TestDashboard_AffinitySelectAll(t *testing.T) {
+	source := newFakeSource()
+	source.affinity = map[int32][]int{200: {0}}
+	dashboard := newPriorityDashboard(t, source)
+
+	dashboard.handleKey("a")
+	dashboard.handleKey("a")
+	if !dashboard.affinity.allSelected() {
+		t.Fatalf("Expected a to select all cores, got %q", dashboard.affinity.list.Rows)
+	}
+	dashboard.handleKey("a")
+	dashboard.handleKey("<Escape>")
+	if dashboard.affinity != nil || !slices.Equal(source.affinity[200], []int{0}) {
+		t.Errorf("Expected Escape to close the dialog without changes, got %v", source.affinity[200])
+	}
+}
+
+func TestDashboard_AffinityWiderThanMeters(t *testing.T) {
+	source := newFakeSource()
+	source.affinity = map[int32][]int{200: {5}}
+	dashboard := newPriorityDashboard(t, source)
+
+	// Привязка к ядру за пределами индикаторов не теряется
+	dashboard.handleKey("a")
+	if got := len(dashboard.affinity.cpus); got != 6 {
+		t.Fatalf("Expected 6 cores in the dialog, got %d", got)
+	}
+	dashboard.handleKey("<Enter>")
+	if !slices.Equal(source.affinity[200], []int{5}) {
+		t.Errorf("Expected affinity to be kept, got %v", source.affinity[200])
+	}
+}
+
+func TestDashboard_AffinityError(t *testing.T) {
+	dashboard := newPriorityDashboard(t, newFakeSource())
+
+	// Источник не знает привязки PID 200 - диалог не открывается
+	dashboard.handleKey("a")
+	if dashboard.affinity != nil {
+		t.Error("Expected no dialog when affinity cannot be read")
+	}
+}
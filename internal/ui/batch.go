@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+
+	"github.com/bonefabric/htop/internal/export"
+	"github.com/bonefabric/htop/internal/record"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// headlessUI - UIProvider без терминала для пакетного режима: ничего не рисует
+// и не получает событий
+type headlessUI struct{}
+
+func (headlessUI) Init() error                 { return nil }
+func (headlessUI) Close()                      {}
+func (headlessUI) PollEvents() <-chan ui.Event { return nil }
+func (headlessUI) Render(...ui.Drawable)       {}
+
+// RunBatch запускает пакетный режим как top -b: собирает данные opts.Iterations
+// раз с интервалом обновления и печатает индикаторы и таблицу процессов
+// обычным текстом в out. При opts.Iterations == 0 работает до прерывания.
+// Если задан opts.Export, снимки выгружаются в машиночитаемом формате.
+func RunBatch(out io.Writer, opts Options) error {
+	write := func(d *Dashboard, iteration int, s system.Snapshot) error {
+		return d.writeBatch(out, iteration, opts.Iterations, s.Time)
+	}
+	if opts.RecordPath != "" {
+		writer, err := record.Create(opts.RecordPath)
+		if err != nil {
+			return err
+		}
+		defer writer.Close()
+		write = recordFrames(writer)
+	} else if opts.Export != "" {
+		format, err := export.ParseFormat(opts.Export)
+		if err != nil {
+			return err
+		}
+		fields, err := export.ParseFields(opts.ExportFields)
+		if err != nil {
+			return err
+		}
+		encoder := export.NewEncoder(out, format, fields)
+		write = func(d *Dashboard, _ int, s system.Snapshot) error {
+			return encoder.Encode(d.snapshot(s.Time))
+		}
+	}
+
+	cfg, _, err := loadConfig(opts)
+	if err != nil {
+		return err
+	}
+	d, err := newDashboard(headlessUI{}, cfg, opts)
+	if err != nil {
+		return err
+	}
+	return d.runBatch(opts.Iterations, write)
+}
+
+// runBatch выполняет iterations обновлений и передает в write каждый
+// собранный снимок после того, как он отображен
+func (d *Dashboard) runBatch(iterations int, write func(d *Dashboard, iteration int, s system.Snapshot) error) error {
+	for i := 1; iterations == 0 || i <= iterations; i++ {
+		if i > 1 {
+			time.Sleep(d.refreshInterval)
+		}
+		// Без экрана ошибку сбора некому показать, и пакетный режим завершается
+		s := d.collect(context.Background())
+		if s.Err != nil {
+			return s.Err
+		}
+		d.apply(s)
+		d.render()
+		if err := write(d, i, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordFrames возвращает функцию для runBatch, которая записывает снимок
+// целиком: все процессы без учета фильтров, чтобы при воспроизведении их можно
+// было применить заново, и ошибку получения списка процессов
+func recordFrames(writer *record.Writer) func(d *Dashboard, iteration int, s system.Snapshot) error {
+	return func(_ *Dashboard, _ int, s system.Snapshot) error {
+		return writer.Write(record.FrameFromSnapshot(s))
+	}
+}
+
+// snapshot возвращает данные последнего обновления: процессы берутся
+// с учетом фильтров и сортировки, как они показаны в списке
+func (d *Dashboard) snapshot(now time.Time) export.Snapshot {
+	return export.Snapshot{
+		Time:      now,
+		CPU:       d.cpuPercents,
+		Memory:    d.memInfo,
+		Processes: d.processes,
+	}
+}
+
+// writeBatch печатает результат одного обновления: время, загрузку ядер,
+// память и таблицу процессов с теми же колонками, что и в интерфейсе
+func (d *Dashboard) writeBatch(out io.Writer, iteration, iterations int, now time.Time) error {
+	w := bufio.NewWriter(out)
+
+	total := "∞"
+	if iterations > 0 {
+		total = fmt.Sprintf("%d", iterations)
+	}
+	fmt.Fprintf(w, "htop - %s, iteration %d/%s, %d processes\n",
+		now.Format("15:04:05"), iteration, total, len(d.allProcesses))
+
+	cores := make([]string, len(d.cpuPercents))
+	for i, percent := range d.cpuPercents {
+		cores[i] = fmt.Sprintf("CPU%d %5.1f%%", i, percent)
+	}
+	// Ядра печатаются строками по столбцам, как индикаторы в интерфейсе
+	perLine := d.config.Layout.CPUColumns
+	for i := 0; i < len(cores); i += perLine {
+		end := i + perLine
+		if end > len(cores) {
+			end = len(cores)
+		}
+		fmt.Fprintln(w, strings.Join(cores[i:end], "  "))
+	}
+
+	if d.memInfo != nil {
+		fmt.Fprintf(w, "Mem %5.1f%% [%s / %s], free %s\n", d.memInfo.UsedPercent,
+			formatBytes(d.memInfo.Used), formatBytes(d.memInfo.Total), formatBytes(d.memInfo.Available))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, d.processHeader.Text)
+	for _, row := range d.processList.Rows {
+		fmt.Fprintln(w, row)
+	}
+	fmt.Fprintln(w)
+
+	return w.Flush()
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+func TestDashboard_WriteBatch(t *testing.T) {
+	cfg := config.Default()
+	cfg.Columns = []string{"PID", "USER", "COMMAND"}
+	dashboard, err := newDashboard(headlessUI{}, cfg, Options{})
+	if err != nil {
+		t.Fatalf("newDashboard() вернул ошибку: %v", err)
+	}
+	dashboard.cpuPercents = []float64{12.5, 100, 3}
+	dashboard.memInfo = &mem.VirtualMemoryStat{Total: 4 << 30, Used: 1 << 30, Available: 3 << 30, UsedPercent: 25}
+	dashboard.allProcesses = []system.ProcessInfo{
+		{PID: 1, User: "root", Cmdline: "/sbin/init"},
+		{PID: 42, User: "alice", Cmdline: "vim notes.txt\nhtop - fake"},
+	}
+	dashboard.refreshProcessList()
+
+	var out bytes.Buffer
+	now := time.Date(2024, 5, 1, 12, 30, 15, 0, time.Local)
+	if err := dashboard.writeBatch(&out, 2, 5, now); err != nil {
+		t.Fatalf("writeBatch() вернул ошибку: %v", err)
+	}
+
+	want := "htop - 12:30:15, iteration 2/5, 2 processes\n" +
+		"CPU0  12.5%  CPU1 100.0%\n" +
+		"CPU2   3.0%\n" +
+		"Mem  25.0% [1.0 GiB / 4.0 GiB], free 3.0 GiB\n" +
+		"\n" +
+		"    PID USER      Command\n" +
+		"      1 root      /sbin/init\n" +
+		"     42 alice     vim notes.txt htop - fake\n" +
+		"\n"
+	if out.String() != want {
+		t.Errorf("Unexpected batch output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestDashboard_RunBatch(t *testing.T) {
+	dashboard, err := newDashboard(headlessUI{}, config.Default(), Options{Delay: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("newDashboard() вернул ошибку: %v", err)
+	}
+
+	var out bytes.Buffer
+	write := func(d *Dashboard, iteration int, s system.Snapshot) error {
+		return d.writeBatch(&out, iteration, 2, s.Time)
+	}
+	if err := dashboard.runBatch(2, write); err != nil {
+		t.Fatalf("runBatch() вернул ошибку: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{"iteration 1/2", "iteration 2/2", formatHeader(dashboard.columns)} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected batch output to contain %q, got:\n%s", want, text)
+		}
+	}
+	if n := strings.Count(text, "\nhtop - ") + 1; n != 2 {
+		t.Errorf("Expected exactly two iterations, got %d", n)
+	}
+}
+
+func TestDashboard_Snapshot(t *testing.T) {
+	dashboard, err := newDashboard(headlessUI{}, config.Default(), Options{User: "alice", SortColumn: "PID"})
+	if err != nil {
+		t.Fatalf("newDashboard() вернул ошибку: %v", err)
+	}
+	dashboard.cpuPercents = []float64{10}
+	dashboard.allProcesses = []system.ProcessInfo{
+		{PID: 7, User: "alice"},
+		{PID: 1, User: "root"},
+		{PID: 3, User: "alice"},
+	}
+	dashboard.refreshProcessList()
+
+	now := time.Now()
+	snapshot := dashboard.snapshot(now)
+	if !snapshot.Time.Equal(now) || len(snapshot.CPU) != 1 {
+		t.Errorf("Unexpected snapshot meters: %+v", snapshot)
+	}
+	// В выгрузку попадает то же, что видно в списке: с фильтрами и сортировкой
+	if !equalPIDs(pids(snapshot.Processes), []int32{3, 7}) {
+		t.Errorf("Expected visible processes [3 7], got %v", pids(snapshot.Processes))
+	}
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	ui "github.com/gizak/termui/v3"
+
+	"github.com/bonefabric/htop/internal/config"
+)
+
+// colorsByName сопоставляет названия цветов из настроек цветам termui
+var colorsByName = map[string]ui.Color{
+	"black":   ui.ColorBlack,
+	"red":     ui.ColorRed,
+	"green":   ui.ColorGreen,
+	"yellow":  ui.ColorYellow,
+	"blue":    ui.ColorBlue,
+	"magenta": ui.ColorMagenta,
+	"cyan":    ui.ColorCyan,
+	"white":   ui.ColorWhite,
+}
+
+// colorScheme описывает цветовую индикацию нагрузки
+type colorScheme struct {
+	thresholds [3]int      // пороги medium, high, critical в процентах
+	colors     [4]ui.Color // цвета low, medium, high, critical
+}
+
+// defaultColors - цветовая схема по умолчанию
+var defaultColors = newColorScheme(config.Default().Colors)
+
+// newColorScheme создает цветовую схему из настроек. Названия цветов
+// проверяются при загрузке настроек, неизвестные заменяются белым.
+func newColorScheme(c config.Colors) colorScheme {
+	return colorScheme{
+		thresholds: [3]int{c.MediumThreshold, c.HighThreshold, c.CriticalThreshold},
+		colors:     [4]ui.Color{colorByName(c.Low), colorByName(c.Medium), colorByName(c.High), colorByName(c.Critical)},
+	}
+}
+
+// colorByName возвращает цвет termui по названию
+func colorByName(name string) ui.Color {
+	if color, ok := colorsByName[name]; ok {
+		return color
+	}
+	return ui.ColorWhite
+}
+
+// colorFor возвращает цвет для процента загрузки
+func (s colorScheme) colorFor(percent int) ui.Color {
+	switch {
+	case percent >= s.thresholds[2]:
+		return s.colors[3]
+	case percent >= s.thresholds[1]:
+		return s.colors[2]
+	case percent >= s.thresholds[0]:
+		return s.colors[1]
+	default:
+		return s.colors[0]
+	}
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"testing"
+
+	ui "github.com/gizak/termui/v3"
+
+	"github.com/bonefabric/htop/internal/config"
+)
+
+func TestColorScheme_Custom(t *testing.T) {
+	scheme := newColorScheme(config.Colors{
+		MediumThreshold:   20,
+		HighThreshold:     40,
+		CriticalThreshold: 60,
+		Low:               "blue",
+		Medium:            "cyan",
+		High:              "yellow",
+		Critical:          "red",
+	})
+
+	tests := []struct {
+		percent  int
+		expected ui.Color
+	}{
+		{10, ui.ColorBlue},
+		{20, ui.ColorCyan},
+		{45, ui.ColorYellow},
+		{60, ui.ColorRed},
+	}
+	for _, tt := range tests {
+		if got := scheme.colorFor(tt.percent); got != tt.expected {
+			t.Errorf("colorFor(%d) = %v, want %v", tt.percent, got, tt.expected)
+		}
+	}
+}
+
+func TestColorNamesAreMapped(t *testing.T) {
+	for _, name := range config.ColorNames {
+		if _, ok := colorsByName[name]; !ok {
+			t.Errorf("Color %q from config has no termui mapping", name)
+		}
+	}
+}
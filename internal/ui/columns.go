@@ -0,0 +1,315 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// column описывает колонку списка процессов: заголовок, ширину, выравнивание,
+// способ форматирования значения и сортировку по колонке
+type column struct {
+	ID          string // идентификатор колонки в настройках
+	Title       string
+	Description string // пояснение для экрана настройки колонок
+	Width       int    // 0 - колонка переменной ширины, занимает оставшееся место
+	AlignRight  bool   // числовые колонки выравниваются по правому краю
+	SortKey     string // горячая клавиша сортировки, пустая - только переключением
+	DefaultDesc bool   // сортировка по убыванию при выборе колонки
+	format      func(p system.ProcessInfo) string
+	compare     func(a, b system.ProcessInfo) int
+}
+
+// flexWidth - ширина колонки переменной ширины, если она стоит не последней
+const flexWidth = 30
+
+// columnRegistry содержит все доступные колонки. Чтобы добавить колонку,
+// достаточно добавить запись сюда - она появится на экране настройки и в
+// режимах сортировки. Сортировка переключается по колонкам в порядке записей.
+var columnRegistry = []column{
+	{ID: "PID", Title: "PID", Description: "Process ID", Width: 7, AlignRight: true,
+		SortKey: "N",
+		format:  func(p system.ProcessInfo) string { return fmt.Sprintf("%d", p.PID) },
+		compare: func(a, b system.ProcessInfo) int { return cmp.Compare(a.PID, b.PID) }},
+	{ID: "PPID", Title: "PPID", Description: "Parent process ID", Width: 7, AlignRight: true,
+		format:  func(p system.ProcessInfo) string { return fmt.Sprintf("%d", p.PPID) },
+		compare: func(a, b system.ProcessInfo) int { return cmp.Compare(a.PPID, b.PPID) }},
+	{ID: "USER", Title: "USER", Description: "Process owner", Width: 9,
+		format:  func(p system.ProcessInfo) string { return p.User },
+		compare: func(a, b system.ProcessInfo) int { return strings.Compare(a.User, b.User) }},
+	{ID: "PRIORITY", Title: "PRI", Description: "Kernel scheduling priority", Width: 3, AlignRight: true,
+		DefaultDesc: true,
+		format:      func(p system.ProcessInfo) string { return fmt.Sprintf("%d", p.Priority) },
+		compare:     func(a, b system.ProcessInfo) int { return cmp.Compare(a.Priority, b.Priority) }},
+	{ID: "NICE", Title: "NI", Description: "Nice value", Width: 3, AlignRight: true,
+		DefaultDesc: true,
+		format:      func(p system.ProcessInfo) string { return fmt.Sprintf("%d", p.Nice) },
+		compare:     func(a, b system.ProcessInfo) int { return cmp.Compare(a.Nice, b.Nice) }},
+	{ID: "IO_PRIORITY", Title: "IO", Description: "I/O priority class and level", Width: 2,
+		format:  func(p system.ProcessInfo) string { return p.IOPriority.Effective(p.Nice).String() },
+		compare: compareIOPriority},
+	{ID: "VIRT", Title: "VIRT", Description: "Virtual memory size", Width: 6, AlignRight: true,
+		DefaultDesc: true,
+		format:      func(p system.ProcessInfo) string { return formatBytesShort(p.VSZ) },
+		compare:     func(a, b system.ProcessInfo) int { return cmp.Compare(a.VSZ, b.VSZ) }},
+	{ID: "RES", Title: "RES", Description: "Resident memory size", Width: 6, AlignRight: true,
+		DefaultDesc: true,
+		format:      func(p system.ProcessInfo) string { return formatBytesShort(p.RSS) },
+		compare:     func(a, b system.ProcessInfo) int { return cmp.Compare(a.RSS, b.RSS) }},
+	{ID: "STATE", Title: "S", Description: "Process state", Width: 1,
+		format:  func(p system.ProcessInfo) string { return statusChar(p.Status) },
+		compare: func(a, b system.ProcessInfo) int { return strings.Compare(a.Status, b.Status) }},
+	{ID: "CPU", Title: "CPU%", Description: "CPU usage over the last interval", Width: 5, AlignRight: true,
+		SortKey: "P", DefaultDesc: true,
+		format:  func(p system.ProcessInfo) string { return fmt.Sprintf("%.1f", p.CPU) },
+		compare: func(a, b system.ProcessInfo) int { return cmp.Compare(a.CPU, b.CPU) }},
+	{ID: "MEM", Title: "MEM%", Description: "Resident memory share", Width: 5, AlignRight: true,
+		SortKey: "M", DefaultDesc: true,
+		format:  func(p system.ProcessInfo) string { return fmt.Sprintf("%.1f", p.Memory) },
+		compare: func(a, b system.ProcessInfo) int { return cmp.Compare(a.Memory, b.Memory) }},
+	{ID: "TIME", Title: "TIME+", Description: "Accumulated CPU time", Width: 9, AlignRight: true,
+		SortKey: "T", DefaultDesc: true,
+		format:  func(p system.ProcessInfo) string { return formatCPUTime(p.CPUTime) },
+		compare: func(a, b system.ProcessInfo) int { return cmp.Compare(a.CPUTime, b.CPUTime) }},
+	{ID: "START", Title: "START", Description: "Start time", Width: 5, AlignRight: true,
+		format:  func(p system.ProcessInfo) string { return formatStartTime(p.StartTime, time.Now()) },
+		compare: func(a, b system.ProcessInfo) int { return a.StartTime.Compare(b.StartTime) }},
+	{ID: "THREADS", Title: "THR", Description: "Number of threads", Width: 4, AlignRight: true,
+		DefaultDesc: true,
+		format:      func(p system.ProcessInfo) string { return fmt.Sprintf("%d", p.Threads) },
+		compare:     func(a, b system.ProcessInfo) int { return cmp.Compare(a.Threads, b.Threads) }},
+	{ID: "IO_READ", Title: "RBYTES", Description: "Bytes read from storage", Width: 6, AlignRight: true,
+		DefaultDesc: true,
+		format:      func(p system.ProcessInfo) string { return formatBytesShort(p.ReadBytes) },
+		compare:     func(a, b system.ProcessInfo) int { return cmp.Compare(a.ReadBytes, b.ReadBytes) }},
+	{ID: "IO_WRITE", Title: "WBYTES", Description: "Bytes written to storage", Width: 6, AlignRight: true,
+		DefaultDesc: true,
+		format:      func(p system.ProcessInfo) string { return formatBytesShort(p.WriteBytes) },
+		compare:     func(a, b system.ProcessInfo) int { return cmp.Compare(a.WriteBytes, b.WriteBytes) }},
+	{ID: "CGROUP", Title: "CGROUP", Description: "Control group path", Width: 24,
+		format:  func(p system.ProcessInfo) string { return p.Cgroup },
+		compare: func(a, b system.ProcessInfo) int { return strings.Compare(a.Cgroup, b.Cgroup) }},
+	{ID: "NAME", Title: "NAME", Description: "Executable name", Width: 15,
+		format: func(p system.ProcessInfo) string { return p.Name },
+		compare: func(a, b system.ProcessInfo) int {
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		}},
+	{ID: "COMMAND", Title: "Command", Description: "Full command line", format: processCommand,
+		compare: func(a, b system.ProcessInfo) int { return strings.Compare(processCommand(a), processCommand(b)) }},
+}
+
+// compareIOPriority сравнивает действующие приоритеты ввода-вывода: сначала
+// класс (realtime, best-effort, idle), затем уровень. Меньшее значение -
+// более высокий приоритет.
+func compareIOPriority(a, b system.ProcessInfo) int {
+	pa, pb := a.IOPriority.Effective(a.Nice), b.IOPriority.Effective(b.Nice)
+	if c := cmp.Compare(pa.Class, pb.Class); c != 0 {
+		return c
+	}
+	return cmp.Compare(pa.Level, pb.Level)
+}
+
+// defaultColumnIDs - набор колонок по умолчанию
+var defaultColumnIDs = config.Default().Columns
+
+// findColumn возвращает колонку реестра по идентификатору
+func findColumn(id string) (column, bool) {
+	for _, c := range columnRegistry {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return column{}, false
+}
+
+// columnsByID возвращает колонки в указанном порядке. Неизвестные
+// идентификаторы и повторы пропускаются.
+func columnsByID(ids []string) []column {
+	columns := make([]column, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if c, ok := findColumn(id); ok && !seen[id] {
+			seen[id] = true
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// configColumns возвращает колонки из настроек, сообщая о неизвестных идентификаторах
+func configColumns(ids []string) ([]column, error) {
+	for _, id := range ids {
+		if _, ok := findColumn(id); !ok {
+			return nil, fmt.Errorf("invalid config: columns: unknown column %q", id)
+		}
+	}
+	return columnsByID(ids), nil
+}
+
+// columnIDs возвращает идентификаторы колонок
+func columnIDs(columns []column) []string {
+	ids := make([]string, len(columns))
+	for i, c := range columns {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+// processCommand возвращает командную строку процесса или его имя, если
+// командной строки нет (потоки ядра, зомби)
+func processCommand(p system.ProcessInfo) string {
+	if p.Cmdline != "" {
+		return sanitize(p.Cmdline)
+	}
+	return p.Name
+}
+
+// sanitize заменяет управляющие символы пробелами, чтобы строка таблицы
+// не разрывалась
+func sanitize(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, text)
+}
+
+// fitText обрезает или дополняет текст пробелами до ширины width
+func fitText(text string, width int, alignRight bool) string {
+	n := utf8.RuneCountInString(text)
+	if n > width {
+		runes := []rune(text)
+		if alignRight {
+			// Для чисел важнее младшие разряды - показываем признак обрезки слева
+			return "+" + string(runes[n-width+1:])
+		}
+		return string(runes[:width])
+	}
+	pad := strings.Repeat(" ", width-n)
+	if alignRight {
+		return pad + text
+	}
+	return text + pad
+}
+
+// cellText выравнивает значение ячейки. Колонка переменной ширины
+// не обрезается, только если она последняя.
+func cellText(c column, value string, last bool) string {
+	switch {
+	case c.Width > 0:
+		return fitText(value, c.Width, c.AlignRight)
+	case last:
+		return value
+	default:
+		return fitText(value, flexWidth, c.AlignRight)
+	}
+}
+
+// formatHeader формирует строку заголовков колонок
+func formatHeader(columns []column) string {
+	parts := make([]string, len(columns))
+	for i, c := range columns {
+		parts[i] = cellText(c, c.Title, i == len(columns)-1)
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatRow формирует строку процесса. prefix (ветка дерева) добавляется
+// перед значением колонки переменной ширины (командной строки).
+func formatRow(columns []column, p system.ProcessInfo, prefix string) string {
+	parts := make([]string, len(columns))
+	for i, c := range columns {
+		value := c.format(p)
+		if c.Width == 0 {
+			value = prefix + value
+		}
+		parts[i] = cellText(c, value, i == len(columns)-1)
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatBytesShort форматирует байты компактно для колонок таблицы (512K, 1.5G)
+func formatBytesShort(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d", bytes)
+	}
+	value := float64(bytes) / unit
+	exp := 0
+	for value >= unit && exp < 5 {
+		value /= unit
+		exp++
+	}
+	suffix := "KMGTPE"[exp]
+	if value < 10 {
+		return fmt.Sprintf("%.1f%c", value, suffix)
+	}
+	return fmt.Sprintf("%.0f%c", value, suffix)
+}
+
+// formatCPUTime форматирует процессорное время как в htop: "m:ss.cc" или "HhMM:SS"
+func formatCPUTime(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	hours := int(d / time.Hour)
+	minutes := int(d/time.Minute) % 60
+	seconds := int(d/time.Second) % 60
+	if hours > 0 {
+		return fmt.Sprintf("%dh%02d:%02d", hours, minutes, seconds)
+	}
+	centis := int(d/(10*time.Millisecond)) % 100
+	return fmt.Sprintf("%d:%02d.%02d", minutes, seconds, centis)
+}
+
+// formatStartTime показывает время запуска для процессов, запущенных сегодня,
+// и дату для более старых
+func formatStartTime(start, now time.Time) string {
+	if start.IsZero() {
+		return "-"
+	}
+	start = start.In(now.Location())
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := now.Date()
+	if y1 == y2 && m1 == m2 && d1 == d2 {
+		return start.Format("15:04")
+	}
+	return start.Format("Jan02")
+}
+
+// statusChar возвращает однобуквенное обозначение состояния процесса как в ps
+func statusChar(status string) string {
+	// Процесс может иметь несколько состояний через запятую - показываем первое
+	if i := strings.IndexByte(status, ','); i >= 0 {
+		status = status[:i]
+	}
+	switch status {
+	case "running":
+		return "R"
+	case "sleep":
+		return "S"
+	case "blocked":
+		return "D"
+	case "stop":
+		return "T"
+	case "zombie":
+		return "Z"
+	case "idle":
+		return "I"
+	case "lock":
+		return "L"
+	case "wait":
+		return "W"
+	case "":
+		return "?"
+	}
+	r, _ := utf8.DecodeRuneInString(status)
+	return strings.ToUpper(string(r))
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+func TestFitText(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		width      int
+		alignRight bool
+		expected   string
+	}{
+		{"Pad left-aligned", "root", 6, false, "root  "},
+		{"Pad right-aligned", "42", 5, true, "   42"},
+		{"Truncate text", "postgres", 5, false, "postg"},
+		{"Truncate number keeps low digits", "1234567", 5, true, "+4567"},
+		{"Unicode", "пользователь", 4, false, "поль"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fitText(tt.text, tt.width, tt.alignRight); got != tt.expected {
+				t.Errorf("fitText(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatRowAlignsWithHeader(t *testing.T) {
+	p := system.ProcessInfo{
+		PID: 1234, User: "postgres", Priority: 20, Nice: 0,
+		VSZ: 2 * 1024 * 1024 * 1024, RSS: 300 * 1024 * 1024, Status: "sleep",
+		CPU: 12.5, Memory: 3.2, CPUTime: 90 * time.Second, Threads: 8,
+		Cmdline: "postgres: checkpointer",
+	}
+
+	header := formatHeader(columnsByID(defaultColumnIDs))
+	row := formatRow(columnsByID(defaultColumnIDs), p, "")
+
+	// Начало командной строки совпадает с заголовком Command
+	if strings.Index(header, "Command") != strings.Index(row, "postgres: checkpointer") {
+		t.Errorf("Command column is misaligned:\n%s\n%s", header, row)
+	}
+	for _, want := range []string{"1234", "postgres", "2.0G", "300M", " S ", "12.5", "1:30.00"} {
+		if !strings.Contains(row, want) {
+			t.Errorf("Expected row to contain %q, got %q", want, row)
+		}
+	}
+
+	// Префикс дерева добавляется перед командой
+	treeRow := formatRow(columnsByID(defaultColumnIDs), p, "└─ ")
+	if !strings.Contains(treeRow, "└─ postgres: checkpointer") {
+		t.Errorf("Expected tree prefix before command, got %q", treeRow)
+	}
+}
+
+func TestProcessCommandFallsBackToName(t *testing.T) {
+	if got := processCommand(system.ProcessInfo{Name: "kworker/0:1"}); got != "kworker/0:1" {
+		t.Errorf("Expected process name for empty command line, got %q", got)
+	}
+}
+
+func TestFormatBytesShort(t *testing.T) {
+	tests := []struct {
+		bytes    uint64
+		expected string
+	}{
+		{500, "500"},
+		{1024, "1.0K"},
+		{512 * 1024, "512K"},
+		{1536 * 1024 * 1024, "1.5G"},
+		{20 * 1024 * 1024 * 1024, "20G"},
+	}
+	for _, tt := range tests {
+		if got := formatBytesShort(tt.bytes); got != tt.expected {
+			t.Errorf("formatBytesShort(%d) = %s, want %s", tt.bytes, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatCPUTime(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{0, "0:00.00"},
+		{1530 * time.Millisecond, "0:01.53"},
+		{61 * time.Second, "1:01.00"},
+		{3*time.Hour + 25*time.Minute + 7*time.Second, "3h25:07"},
+	}
+	for _, tt := range tests {
+		if got := formatCPUTime(tt.duration); got != tt.expected {
+			t.Errorf("formatCPUTime(%v) = %s, want %s", tt.duration, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatStartTime(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 18, 0, 0, 0, time.UTC)
+
+	if got := formatStartTime(time.Date(2024, time.March, 15, 9, 5, 0, 0, time.UTC), now); got != "09:05" {
+		t.Errorf("Expected time for today's process, got %s", got)
+	}
+	if got := formatStartTime(time.Date(2024, time.March, 2, 9, 5, 0, 0, time.UTC), now); got != "Mar02" {
+		t.Errorf("Expected date for older process, got %s", got)
+	}
+	if got := formatStartTime(time.Time{}, now); got != "-" {
+		t.Errorf("Expected placeholder for unknown start time, got %s", got)
+	}
+}
+
+func TestStatusChar(t *testing.T) {
+	tests := map[string]string{
+		"running":       "R",
+		"sleep":         "S",
+		"zombie":        "Z",
+		"stop":          "T",
+		"blocked":       "D",
+		"sleep,running": "S",
+		"":              "?",
+	}
+	for status, expected := range tests {
+		if got := statusChar(status); got != expected {
+			t.Errorf("statusChar(%q) = %s, want %s", status, got, expected)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bonefabric/htop/internal/config"
+)
+
+func TestNewDashboardWithConfig(t *testing.T) {
+	cfg := config.Default()
+	cfg.Layout.CPUColumns, cfg.Layout.GaugeWidth = 4, 30
+	cfg.Columns = []string{"PID", "COMMAND"}
+	cfg.Sort = config.Sort{Column: "MEM", Descending: false}
+	cfg.TreeView = true
+	cfg.RefreshInterval = config.Duration(2 * time.Second)
+
+	dashboard, err := NewDashboardWithConfig(NewMockUI(), cfg)
+	if err != nil {
+		t.Fatalf("NewDashboardWithConfig() вернул ошибку: %v", err)
+	}
+
+	if dashboard.sortKey != SortByMemory || dashboard.sortDesc {
+		t.Errorf("Expected ascending memory sort, got %v desc=%v", dashboard.sortKey, dashboard.sortDesc)
+	}
+	if !dashboard.treeView {
+		t.Error("Expected tree view from config")
+	}
+	if got := strings.Join(columnIDs(dashboard.columns), ","); got != "PID,COMMAND" {
+		t.Errorf("Expected columns from config, got %s", got)
+	}
+	if width := dashboard.processList.GetRect().Dx(); width != 120 {
+		t.Errorf("Expected process list width 4*30, got %d", width)
+	}
+	if len(dashboard.cpuCharts) > 1 {
+		if x := dashboard.cpuCharts[1].GetRect().Min.X; x != 30 {
+			t.Errorf("Expected second CPU gauge at x=30, got %d", x)
+		}
+	}
+}
+
+func TestNewDashboardWithConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*config.Config)
+		wantErr string
+	}{
+		{"Unknown column", func(c *config.Config) { c.Columns = []string{"PID", "FOO"} }, `unknown column "FOO"`},
+		{"Unknown sort column", func(c *config.Config) { c.Sort.Column = "BAR" }, `sort.column: unknown column "BAR"`},
+		{"Invalid layout", func(c *config.Config) { c.Layout.CPUColumns = 0 }, "layout.cpu_columns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			tt.modify(&cfg)
+			mock := NewMockUI()
+			_, err := NewDashboardWithConfig(mock, cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if mock.initCalled {
+				t.Error("UI must not be initialized with invalid config")
+			}
+		})
+	}
+}
+
+func TestSortNamesMatchColumns(t *testing.T) {
+	for _, f := range sortFields {
+		if _, ok := findColumn(f.Name); !ok {
+			t.Errorf("Sort mode %q has no matching column", f.Name)
+		}
+	}
+}
+
+func TestDashboard_SavesConfigOnChange(t *testing.T) {
+	dashboard, err := NewDashboardWithUI(NewMockUI())
+	if err != nil {
+		t.Fatalf("Failed to create dashboard: %v", err)
+	}
+	dashboard.configPath = filepath.Join(t.TempDir(), "config.json")
+
+	dashboard.handleKey("M")
+	dashboard.handleKey("t")
+	dashboard.handleKey("<F2>")
+	dashboard.handleKey("<Space>") // убираем первую колонку (PID)
+
+	saved, err := config.Load(dashboard.configPath)
+	if err != nil {
+		t.Fatalf("Failed to load saved config: %v", err)
+	}
+	if saved.Sort.Column != "MEM" || !saved.Sort.Descending {
+		t.Errorf("Expected memory sort to be saved, got %+v", saved.Sort)
+	}
+	if !saved.TreeView {
+		t.Error("Expected tree view to be saved")
+	}
+	if saved.Columns[0] == "PID" || len(saved.Columns) != len(defaultColumnIDs)-1 {
+		t.Errorf("Expected PID column to be removed in saved config, got %v", saved.Columns)
+	}
+}
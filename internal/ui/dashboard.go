@@ -1,15 +1,20 @@
 package ui
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"image"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
-	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/mem"
 
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/record"
 	"github.com/bonefabric/htop/internal/system"
 )
 
@@ -56,103 +61,277 @@ func formatBytes(bytes uint64) string {
 
 // Dashboard представляет главный экран приложения
 type Dashboard struct {
-	ui            UIProvider
-	cpuCharts     []*widgets.Gauge
-	memChart      *widgets.Gauge
-	processList   *widgets.List
-	selectedRow   int // Индекс выбранного процесса
-	signalMenu    *widgets.List
-	showSignalMenu bool
-	selectedSignal int
-	processes     []system.ProcessInfo // Сохраняем список процессов
+	ui              UIProvider
+	cpuCharts       []*widgets.Gauge
+	memChart        *widgets.Gauge
+	cpuHistory      []*historyMeter // История загрузки ядер для видов sparkline и chart
+	cpuTotalChart   *widgets.Gauge  // Общая загрузка CPU, если индикаторы ядер не помещаются
+	cpuTotalHistory *historyMeter
+	layout          layout // Текущая геометрия виджетов
+	memHistory      *historyMeter
+	detail          *detailPane        // Панель сведений о процессе, nil - закрыта
+	processHeader   *widgets.Paragraph // Заголовки колонок над списком процессов
+	processList     *widgets.List
+	selectedRow     int // Индекс выбранного процесса
+	signalMenu      *widgets.List
+	showSignalMenu  bool
+	selectedSignal  int
+	signalOrder     []system.Signal // Сигналы в порядке строк меню: избранные, затем остальные
+	signalQuery     string          // Набранные номер или имя сигнала
+	signalNotFound  bool            // По signalQuery ничего не найдено
+	ioMenu          *widgets.List   // Меню приоритетов ввода-вывода
+	showIOMenu      bool
+	menuTargets     actionTargets        // Цели меню сигналов или приоритетов, запомненные при открытии
+	affinity        *affinityDialog      // Диалог привязки к ядрам, nil - закрыт
+	tagged          map[int32]time.Time  // Отмеченные процессы: PID и время запуска
+	summary         *widgets.List        // Итоги действия над отмеченными процессами, nil - скрыты
+	confirm         *signalConfirm       // Подтверждение сигнала, nil - скрыто
+	statusLine      *widgets.Paragraph   // Строка состояния с последним сообщением
+	statusShown     bool                 // Сообщение еще показывается в строке состояния
+	statusTimer     *time.Timer          // Таймер скрытия сообщения из строки состояния
+	messages        []statusMessage      // История сообщений, последнее в конце
+	history         *widgets.List        // Окно истории сообщений, nil - закрыто
+	allProcesses    []system.ProcessInfo // Все собранные процессы
+	processes       []system.ProcessInfo // Процессы, отображаемые в списке (после фильтра)
+	selectedPID     int32                // PID выбранного процесса, курсор следует за ним при пересортировке
+	sortKey         SortKey
+	sortDesc        bool
+	inputMode       inputMode // Активный режим ввода поиска или фильтра
+	inputText       string
+	searchText      string
+	searchFailed    bool
+	filterText      string
+	treeView        bool           // Отображение процессов деревом по PPID
+	treePrefixes    []string       // Префиксы веток для строк d.processes в режиме дерева
+	collapsed       map[int32]bool // Свернутые ветки дерева
+	source          system.Source  // Источник метрик и операций над процессами
+	columns         []column       // Отображаемые колонки списка процессов
+	setupMenu       *widgets.List  // Экран настройки колонок
+	showSetup       bool
+	setupRow        int
+	config          config.Config // Текущие настройки, сохраняются при изменении из интерфейса
+	configPath      string        // Пустой путь - настройки не сохраняются
+	colors          colorScheme
+	options         Options // Параметры запуска
+	refreshInterval time.Duration
+	cpuPercents     []float64              // Последние значения загрузки ядер
+	memInfo         *mem.VirtualMemoryStat // Последние данные о памяти
+	collect         system.CollectFunc     // Сбор снимков состояния системы
+	ctx             context.Context        // Отменяется при выходе из Run, прерывая фоновое чтение
+	details         chan detailResult      // Сведения о процессе, прочитанные в фоне
+	player          *record.Player         // Воспроизводимая запись, nil - живые данные
+	replayTimer     *time.Timer            // Таймер показа следующего кадра записи
+	width, height   int                    // Размер экрана, под который рассчитана геометрия
 }
 
-// NewDashboard создает новый экземпляр Dashboard
-func NewDashboard() (*Dashboard, error) {
-	return NewDashboardWithUI(&RealUI{})
+// NewDashboard создает новый экземпляр Dashboard с настройками из файла
+// и параметрами запуска
+func NewDashboard(opts Options) (*Dashboard, error) {
+	cfg, path, err := loadConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	d, err := newDashboard(&RealUI{}, cfg, opts)
+	if err != nil {
+		return nil, err
+	}
+	d.configPath = path
+	return d, nil
+}
+
+// loadConfig загружает настройки из файла, указанного в параметрах запуска,
+// или из файла по умолчанию. Возвращает настройки и путь к файлу.
+func loadConfig(opts Options) (config.Config, string, error) {
+	path := opts.ConfigPath
+	if path == "" {
+		var err error
+		if path, err = config.DefaultPath(); err != nil {
+			return config.Config{}, "", err
+		}
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return config.Config{}, "", err
+	}
+	return cfg, path, nil
 }
 
 // NewDashboardWithUI создает новый экземпляр Dashboard с указанным UI провайдером
+// и настройками по умолчанию
 func NewDashboardWithUI(provider UIProvider) (*Dashboard, error) {
+	return NewDashboardWithConfig(provider, config.Default())
+}
+
+// NewDashboardWithConfig создает новый экземпляр Dashboard с указанным UI провайдером
+// и настройками
+func NewDashboardWithConfig(provider UIProvider, cfg config.Config) (*Dashboard, error) {
+	return newDashboard(provider, cfg, Options{})
+}
+
+// NewDashboardWithSource создает новый экземпляр Dashboard, получающий данные
+// из source вместо текущей машины
+func NewDashboardWithSource(provider UIProvider, source system.Source, cfg config.Config) (*Dashboard, error) {
+	return newDashboardWithSource(provider, source, cfg, Options{})
+}
+
+// newDashboard создает Dashboard с настройками cfg, переопределенными параметрами
+// запуска opts, и данными текущей машины
+func newDashboard(provider UIProvider, cfg config.Config, opts Options) (*Dashboard, error) {
+	return newDashboardWithSource(provider, nil, cfg, opts)
+}
+
+// newDashboardWithSource создает Dashboard с данными из source. Если source
+// не задан, используется текущая машина.
+func newDashboardWithSource(provider UIProvider, source system.Source, cfg config.Config, opts Options) (*Dashboard, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %v", err)
+	}
+	columns, err := configColumns(cfg.Columns)
+	if err != nil {
+		return nil, err
+	}
+	sortKey, ok := sortKeyByName(cfg.Sort.Column)
+	if !ok {
+		return nil, fmt.Errorf("invalid config: sort.column: unknown column %q", cfg.Sort.Column)
+	}
+	sortDesc := cfg.Sort.Descending
+	if opts.SortColumn != "" {
+		key, ok := sortKeyByName(strings.ToUpper(opts.SortColumn))
+		if !ok {
+			return nil, fmt.Errorf("unknown sort column %q", opts.SortColumn)
+		}
+		if key != sortKey {
+			sortKey, sortDesc = key, sortFields[key].DefaultDesc
+		}
+	}
+	refreshInterval := time.Duration(cfg.RefreshInterval)
+	if opts.Delay > 0 {
+		refreshInterval = opts.Delay
+	}
+	if source == nil {
+		cpuMode := system.CPUPerCore
+		if cfg.CPUMode == config.CPUModeTotal {
+			cpuMode = system.CPUTotal
+		}
+		source = system.NewLiveSource(cpuMode)
+		if opts.ReplayPath != "" {
+			var err error
+			if source, err = record.OpenPlayer(opts.ReplayPath); err != nil {
+				return nil, err
+			}
+		} else if opts.ProcRoot != "" {
+			var err error
+			if source, err = system.OpenProcSource(opts.ProcRoot, cpuMode); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if err := provider.Init(); err != nil {
 		return nil, fmt.Errorf("failed to initialize termui: %v", err)
 	}
 
 	// Получаем количество ядер процессора
-	counts, err := cpu.Counts(true)
+	counts, err := source.CPUCount()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get CPU count: %v", err)
 	}
 
 	d := &Dashboard{
-		ui:            provider,
-		cpuCharts:     make([]*widgets.Gauge, counts),
-		memChart:      widgets.NewGauge(),
-		processList:   widgets.NewList(),
-		selectedRow:   0,
-		signalMenu:    widgets.NewList(),
-		showSignalMenu: false,
-		selectedSignal: 0,
-	}
-
-	// Создаем и настраиваем индикаторы для каждого ядра
-	// Располагаем их в два столбца
-	gaugeWidth := 50
-	gaugeHeight := 3
-	columnsCount := 2
-	
+		ui:              provider,
+		cpuCharts:       make([]*widgets.Gauge, counts),
+		cpuTotalChart:   widgets.NewGauge(),
+		memChart:        widgets.NewGauge(),
+		processHeader:   widgets.NewParagraph(),
+		processList:     widgets.NewList(),
+		selectedRow:     0,
+		signalMenu:      newSignalMenu(),
+		showSignalMenu:  false,
+		selectedSignal:  0,
+		ioMenu:          newIOMenu(),
+		tagged:          make(map[int32]time.Time),
+		statusLine:      newStatusLine(),
+		statusTimer:     newStatusTimer(),
+		sortKey:         sortKey,
+		sortDesc:        sortDesc,
+		treeView:        cfg.TreeView || opts.Tree,
+		collapsed:       make(map[int32]bool),
+		source:          source,
+		collect:         system.Collector(source),
+		ctx:             context.Background(),
+		details:         make(chan detailResult),
+		columns:         columns,
+		setupMenu:       widgets.NewList(),
+		config:          cfg,
+		colors:          newColorScheme(cfg.Colors),
+		options:         opts,
+		refreshInterval: refreshInterval,
+	}
+
+	period := time.Duration(cfg.Layout.History)
+	d.cpuHistory = make([]*historyMeter, counts)
+	for i := range d.cpuHistory {
+		d.cpuHistory[i] = newHistoryMeter(period, refreshInterval, cfg.Layout.MeterStyle, &d.colors)
+		d.cpuHistory[i].Title = fmt.Sprintf("CPU Core %d", i)
+	}
+	d.cpuTotalHistory = newHistoryMeter(period, refreshInterval, cfg.Layout.MeterStyle, &d.colors)
+	d.cpuTotalHistory.Title = "CPU"
+	d.memHistory = newHistoryMeter(period, refreshInterval, cfg.Layout.MeterStyle, &d.colors)
+	d.memHistory.Title = "Memory Usage"
+
 	for i := 0; i < counts; i++ {
 		d.cpuCharts[i] = widgets.NewGauge()
 		d.cpuCharts[i].Title = fmt.Sprintf("CPU Core %d", i)
-		
-		// Вычисляем позицию для текущего индикатора
-		column := i % columnsCount
-		row := i / columnsCount
-		
-		x1 := column * gaugeWidth
-		y1 := row * gaugeHeight
-		x2 := x1 + gaugeWidth
-		y2 := y1 + gaugeHeight
-		
-		d.cpuCharts[i].SetRect(x1, y1, x2, y2)
 		d.cpuCharts[i].BarColor = ui.ColorGreen
 		d.cpuCharts[i].BorderStyle.Fg = ui.ColorCyan
 		d.cpuCharts[i].TitleStyle.Fg = ui.ColorWhite
 	}
 
-	// Вычисляем высоту, занимаемую CPU индикаторами
-	cpuRowsCount := (counts + columnsCount - 1) / columnsCount // округление вверх
-	totalCPUHeight := cpuRowsCount * gaugeHeight
+	d.cpuTotalChart.Title = fmt.Sprintf("CPU (%d cores)", counts)
+	d.cpuTotalChart.BarColor = ui.ColorGreen
+	d.cpuTotalChart.BorderStyle.Fg = ui.ColorCyan
+	d.cpuTotalChart.TitleStyle.Fg = ui.ColorWhite
 
 	// Настройка Memory виджета
 	d.memChart.Title = "Memory Usage"
-	d.memChart.SetRect(0, totalCPUHeight, 100, totalCPUHeight+3)
 	d.memChart.BarColor = ui.ColorGreen
 	d.memChart.BorderStyle.Fg = ui.ColorCyan
 	d.memChart.TitleStyle.Fg = ui.ColorWhite
 	d.memChart.Label = "Initializing..." // Начальное значение
 
+	d.processHeader.Border = false
+	d.processHeader.Text = formatHeader(d.columns)
+	d.processHeader.TextStyle = ui.NewStyle(ui.ColorBlack, ui.ColorCyan)
+
 	// Настройка списка процессов
 	d.processList.Title = "Processes (↑/↓ to navigate, → for signals)"
-	d.processList.SetRect(0, totalCPUHeight+3, 100, totalCPUHeight+17)
 	d.processList.BorderStyle.Fg = ui.ColorCyan
 	d.processList.TitleStyle.Fg = ui.ColorWhite
 	d.processList.TextStyle = ui.NewStyle(ui.ColorWhite)
 	d.processList.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorGreen)
 	d.processList.WrapText = false
 
-	// Настройка меню сигналов
-	d.signalMenu.Title = "Send Signal (↑/↓ to select, Enter to send, ← to cancel)"
-	d.signalMenu.TextStyle = ui.NewStyle(ui.ColorWhite)
-	d.signalMenu.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorYellow)
-	d.signalMenu.WrapText = false
+	// Настройка экрана выбора колонок
+	d.setupMenu.Title = "Columns (Space toggle, F7/F8 move, Esc close)"
+	d.setupMenu.TextStyle = ui.NewStyle(ui.ColorWhite)
+	d.setupMenu.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorYellow)
+	d.setupMenu.WrapText = false
+
+	d.updateSignalRows()
 
-	// Заполняем список сигналов
-	signalTexts := make([]string, len(system.AvailableSignals))
-	for i, sig := range system.AvailableSignals {
-		signalTexts[i] = fmt.Sprintf("%s - %s", sig.Name, sig.Description)
+	// Геометрия виджетов зависит от размера терминала и пересчитывается при его изменении
+	width, height := defaultDimensions(counts, cfg.Layout)
+	if t, ok := provider.(Terminal); ok {
+		width, height = t.Dimensions()
+	}
+	d.resize(width, height)
+
+	// Запись воспроизводится вместо сбора данных
+	d.player, _ = source.(*record.Player)
+
+	if opts.Monochrome {
+		d.applyMonochrome()
 	}
-	d.signalMenu.Rows = signalTexts
 
 	return d, nil
 }
@@ -163,191 +342,425 @@ func (d *Dashboard) updateSignalMenuPosition() {
 		return
 	}
 
+	rect := d.menuRect(signalMenuWidth, len(d.signalMenu.Rows))
+	d.signalMenu.SetRect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y)
+}
+
+// menuRect возвращает место меню из rows строк справа от курсора в пределах
+// списка процессов. Если меню выше списка, оно обрезается и прокручивается.
+func (d *Dashboard) menuRect(width, rows int) image.Rectangle {
 	// Получаем размеры списка процессов
 	rect := d.processList.GetRect()
-	menuWidth := 40
-	menuHeight := len(system.AvailableSignals) + 2 // +2 для рамки
+	width = min(width, rect.Dx())
+	menuHeight := rows + 2 // +2 для рамки
 
 	// Располагаем меню справа от курсора
-	menuX1 := rect.Max.X - menuWidth
+	menuX1 := rect.Max.X - width
 	menuY1 := rect.Min.Y + d.selectedRow
-	if menuY1+menuHeight > rect.Min.Y+13 { // Если меню выходит за нижнюю границу
-		menuY1 = rect.Min.Y + 13 - menuHeight
+	if menuY1+menuHeight > rect.Max.Y { // Если меню выходит за нижнюю границу
+		menuY1 = rect.Max.Y - menuHeight
+	}
+	if menuY1 < rect.Min.Y {
+		menuY1 = rect.Min.Y
 	}
 
-	d.signalMenu.SetRect(menuX1, menuY1, rect.Max.X, menuY1+menuHeight)
+	return image.Rect(menuX1, menuY1, rect.Max.X, min(menuY1+menuHeight, rect.Max.Y))
 }
 
 // getColorByPercent возвращает цвет в зависимости от процента загрузки
+// по цветовой схеме по умолчанию
 func getColorByPercent(percent int) ui.Color {
-	switch {
-	case percent >= 90:
-		return ui.ColorRed
-	case percent >= 70:
-		return ui.ColorYellow
-	case percent >= 50:
-		return ui.ColorMagenta
-	default:
-		return ui.ColorGreen
-	}
+	return defaultColors.colorFor(percent)
 }
 
 // Run запускает основной цикл обновления Dashboard
 func (d *Dashboard) Run() error {
 	defer d.ui.Close()
 
-	// Создаем тикер для обновления данных
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
+	// Данные собираются в отдельной горутине, чтобы медленный обход процессов
+	// не блокировал обработку клавиш. Отмена контекста останавливает сбор.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.ctx = ctx
+	var snapshots <-chan system.Snapshot
+	var replay <-chan time.Time
+	if d.player != nil {
+		replay = d.startReplay()
+		defer d.replayTimer.Stop()
+	} else {
+		snapshots = system.StartSampler(ctx, d.refreshInterval, d.collect)
+	}
 
 	// Обработка выхода по клавише 'q'
 	uiEvents := d.ui.PollEvents()
+	defer d.statusTimer.Stop()
 
 	for {
 		select {
 		case e := <-uiEvents:
-			if e.Type == ui.KeyboardEvent {
-				if d.showSignalMenu {
-					// Обработка событий в меню сигналов
-					switch e.ID {
-					case "<Left>":
-						d.showSignalMenu = false
-						d.selectedSignal = 0
-					case "<Up>":
-						d.selectedSignal--
-						if d.selectedSignal < 0 {
-							d.selectedSignal = 0
-						}
-						d.signalMenu.SelectedRow = d.selectedSignal
-					case "<Down>":
-						d.selectedSignal++
-						if d.selectedSignal >= len(system.AvailableSignals) {
-							d.selectedSignal = len(system.AvailableSignals) - 1
-						}
-						d.signalMenu.SelectedRow = d.selectedSignal
-					case "<Enter>":
-						if len(d.processes) > d.selectedRow {
-							proc := d.processes[d.selectedRow]
-							sig := system.AvailableSignals[d.selectedSignal]
-							if err := system.SendSignal(proc.PID, sig.Signal); err != nil {
-								log.Printf("Failed to send signal %s to process %d: %v", 
-									sig.Name, proc.PID, err)
-							}
-						}
-						d.showSignalMenu = false
-						d.selectedSignal = 0
-					}
-				} else {
-					// Обработка событий в основном интерфейсе
-					switch e.ID {
-					case "q", "<C-c>":
-						return nil
-					case "<Down>":
-						d.selectedRow++
-						if d.selectedRow >= len(d.processList.Rows) {
-							d.selectedRow = len(d.processList.Rows) - 1
-						}
-						d.processList.ScrollDown()
-					case "<Up>":
-						d.selectedRow--
-						if d.selectedRow < 0 {
-							d.selectedRow = 0
-						}
-						d.processList.ScrollUp()
-					case "<Right>":
-						d.showSignalMenu = true
-						d.selectedSignal = 0
-						d.signalMenu.SelectedRow = 0
-					}
-				}
-				d.processList.SelectedRow = d.selectedRow
-				d.updateSignalMenuPosition()
-
-				// Рендерим все виджеты
-				drawables := make([]ui.Drawable, 0, len(d.cpuCharts)+3)
-				for _, chart := range d.cpuCharts {
-					drawables = append(drawables, chart)
+			switch e.Type {
+			case ui.KeyboardEvent:
+				if d.handleKey(e.ID) {
+					return nil
 				}
-				drawables = append(drawables, d.memChart, d.processList)
-				if d.showSignalMenu {
-					drawables = append(drawables, d.signalMenu)
+				d.render()
+			case ui.ResizeEvent:
+				if size, ok := e.Payload.(ui.Resize); ok {
+					d.handleResize(size.Width, size.Height)
+					d.render()
 				}
-				d.ui.Render(drawables...)
 			}
-		case <-ticker.C:
-			if err := d.update(); err != nil {
-				return err
+		case s := <-snapshots:
+			d.apply(s)
+			d.render()
+		case <-replay:
+			d.nextFrame()
+			d.render()
+		case r := <-d.details:
+			d.applyDetail(r)
+			d.render()
+		case <-d.statusTimer.C:
+			d.expireStatus()
+			d.render()
+		}
+	}
+}
+
+// handleKey обрабатывает нажатие клавиши. Возвращает true, если нужно выйти из приложения.
+func (d *Dashboard) handleKey(id string) bool {
+	if d.inputMode != inputNone {
+		d.handleInputKey(id)
+	} else if d.confirm != nil {
+		d.handleConfirmKey(id)
+	} else if d.summary != nil {
+		d.handleSummaryKey(id)
+	} else if d.history != nil {
+		d.handleHistoryKey(id)
+	} else if d.showSetup {
+		d.handleSetupKey(id)
+	} else if d.showSignalMenu {
+		d.handleSignalMenuKey(id)
+	} else if d.showIOMenu {
+		d.handleIOMenuKey(id)
+	} else if d.affinity != nil {
+		d.handleAffinityKey(id)
+	} else if d.detail != nil {
+		d.handleDetailKey(id)
+	} else if d.player != nil && d.handleReplayKey(id) {
+		// Клавиша управления воспроизведением записи
+	} else {
+		// Обработка событий в основном интерфейсе
+		switch id {
+		case "q", "<C-c>":
+			return true
+		case "<Down>":
+			d.selectRow(d.selectedRow + 1)
+			d.processList.ScrollDown()
+		case "<Up>":
+			d.selectRow(d.selectedRow - 1)
+			d.processList.ScrollUp()
+		case "<Enter>":
+			d.openDetail()
+		case "<Right>":
+			d.openSignalMenu()
+		case "<F7>", "]":
+			d.renice(-1)
+		case "<F8>", "[":
+			d.renice(1)
+		case "i":
+			d.openIOMenu()
+		case "a":
+			d.openAffinity()
+		case "<Space>":
+			d.toggleTag()
+		case "c":
+			d.tagSubtree()
+		case "*":
+			d.tagShown()
+		case "U":
+			d.untagAll()
+		case "<F6>", ">":
+			d.nextSortKey()
+		case "I":
+			d.invertSort()
+		case "/":
+			d.startInput(inputSearch)
+		case "\\":
+			d.startInput(inputFilter)
+		case "<F3>":
+			d.searchNext()
+		case "<F2>", "S":
+			d.openSetup()
+		case "L":
+			d.openHistory()
+		case "t", "<F5>":
+			d.toggleTreeView()
+		case "m":
+			d.nextMeterStyle()
+		case "-":
+			d.setCollapsed(true)
+		case "+", "=":
+			d.setCollapsed(false)
+		case "<Escape>":
+			if d.filterText != "" {
+				d.filterText = ""
+				d.refreshProcessList()
 			}
+		default:
+			if key, ok := sortKeyByHotkey(id); ok {
+				d.setSortKey(key)
+			}
+		}
+	}
+	d.processList.SelectedRow = d.selectedRow
+	d.updateSignalMenuPosition()
+	return false
+}
+
+// selectRow перемещает курсор на строку row с учетом границ списка
+// и запоминает PID выбранного процесса
+func (d *Dashboard) selectRow(row int) {
+	if row >= len(d.processList.Rows) {
+		row = len(d.processList.Rows) - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+	d.selectedRow = row
+	if row < len(d.processes) {
+		d.selectedPID = d.processes[row].PID
+	}
+}
+
+// refreshProcessList сортирует и фильтрует сохраненные процессы, перестраивает
+// строки списка и возвращает курсор на ранее выбранный PID
+func (d *Dashboard) refreshProcessList() {
+	sortProcesses(d.allProcesses, d.sortKey, d.sortDesc)
+	d.processes = filterProcesses(d.allProcesses, d.filterText, d.matchesOptions)
+	d.treePrefixes = nil
+	if d.treeView {
+		d.processes, d.treePrefixes = buildTree(d.processes, d.collapsed)
+	}
+
+	processTexts := make([]string, 0, len(d.processes))
+	row := -1
+	for i, p := range d.processes {
+		if p.PID == d.selectedPID {
+			row = i
 		}
+		prefix := ""
+		if d.treePrefixes != nil {
+			prefix = d.treePrefixes[i]
+		}
+		text := formatRow(d.columns, p, prefix)
+		if d.isTagged(p) {
+			text = styleRow(text, d.tagStyle())
+		}
+		processTexts = append(processTexts, text)
+	}
+	d.processList.Rows = processTexts
+	d.updateProcessListTitle()
+
+	// Если выбранный процесс завершился, оставляем курсор на прежней позиции
+	if row < 0 {
+		row = d.selectedRow
+	}
+	d.selectRow(row)
+	d.processList.SelectedRow = d.selectedRow
+}
+
+// updateProcessListTitle обновляет заголовок списка процессов: режим сортировки,
+// активный фильтр и строку ввода поиска
+func (d *Dashboard) updateProcessListTitle() {
+	title := fmt.Sprintf("Processes [%s]", d.sortIndicator())
+	if d.treeView {
+		title = fmt.Sprintf("Processes [tree, %s]", d.sortIndicator())
+	}
+	if d.options.User != "" {
+		title += fmt.Sprintf(" [user: %s]", d.options.User)
+	}
+	if len(d.options.PIDs) > 0 {
+		pids := make([]string, len(d.options.PIDs))
+		for i, pid := range d.options.PIDs {
+			pids[i] = strconv.Itoa(int(pid))
+		}
+		title += fmt.Sprintf(" [pid: %s]", strings.Join(pids, ","))
+	}
+	if d.filterText != "" && d.inputMode != inputFilter {
+		title += fmt.Sprintf(" [filter: %s, %d/%d]", d.filterText, len(d.processes), len(d.allProcesses))
+	}
+	if len(d.tagged) > 0 {
+		title += fmt.Sprintf(" [tagged: %d]", len(d.tagged))
+	}
+
+	if d.player != nil {
+		title += d.replayIndicator()
+	}
+
+	switch d.inputMode {
+	case inputSearch:
+		title += " Search: " + d.inputText + "_"
+		if d.searchFailed {
+			title += " (not found)"
+		}
+	case inputFilter:
+		title += " Filter: " + d.inputText + "_"
+	default:
+		if d.player != nil {
+			title += " (p pause, ,/. step, [/] ±1m, {/} speed)"
+		} else {
+			title += " (↑/↓ to navigate, Enter details, → for signals, Space tag, F7/F8 nice, i I/O, a affinity, / search, \\ filter, t tree, L messages)"
+		}
+	}
+	d.processList.Title = title
+}
+
+// saveConfig записывает настройки в файл. Вызывающий сначала переносит в
+// d.config то, что пользователь изменил в интерфейсе: состояние, заданное
+// параметрами запуска (-t, -s), в файл не попадает, пока его не изменят.
+func (d *Dashboard) saveConfig() {
+	if d.configPath == "" {
+		return
+	}
+	if err := config.Save(d.configPath, d.config); err != nil {
+		d.report(severityError, "Failed to save config: %v", err)
+	}
+}
+
+// render отрисовывает все видимые виджеты
+func (d *Dashboard) render() {
+	drawables := make([]ui.Drawable, 0, len(d.cpuCharts)+5)
+	gauges := d.config.Layout.MeterStyle == config.MeterGauge
+	for i := range d.cpuCharts {
+		if d.layout.cpu[i].Empty() {
+			continue
+		}
+		if gauges {
+			drawables = append(drawables, d.cpuCharts[i])
+		} else {
+			drawables = append(drawables, d.cpuHistory[i])
+		}
+	}
+	for _, meter := range []struct {
+		rect          image.Rectangle
+		gauge, series ui.Drawable
+	}{
+		{d.layout.cpuTotal, d.cpuTotalChart, d.cpuTotalHistory},
+		{d.layout.mem, d.memChart, d.memHistory},
+	} {
+		if meter.rect.Empty() {
+			continue
+		}
+		if gauges {
+			drawables = append(drawables, meter.gauge)
+		} else {
+			drawables = append(drawables, meter.series)
+		}
+	}
+	drawables = append(drawables, d.processHeader, d.processList, d.statusLine)
+	if d.detail != nil {
+		drawables = append(drawables, d.detailDrawables()...)
+	}
+	if d.showSignalMenu {
+		d.updateSignalMenuPosition()
+		drawables = append(drawables, d.signalMenu)
+	}
+	if d.showIOMenu {
+		d.updateIOMenuPosition()
+		drawables = append(drawables, d.ioMenu)
+	}
+	if d.affinity != nil {
+		d.updateAffinityPosition()
+		drawables = append(drawables, d.affinity.list)
+	}
+	if d.showSetup {
+		drawables = append(drawables, d.setupMenu)
+	}
+	if d.history != nil {
+		d.updateHistoryPosition()
+		drawables = append(drawables, d.history)
+	}
+	if d.summary != nil {
+		d.updateSummaryPosition()
+		drawables = append(drawables, d.summary)
+	}
+	if d.confirm != nil {
+		d.updateConfirmPosition()
+		drawables = append(drawables, d.confirm.list)
 	}
+	d.ui.Render(drawables...)
 }
 
-// update обновляет все виджеты Dashboard
+// update синхронно собирает данные и обновляет все виджеты Dashboard.
+// Возвращает ошибку сбора, которую apply уже показал в строке состояния.
 func (d *Dashboard) update() error {
-	// Обновляем CPU для каждого ядра
-	cpuPercents, err := cpu.Percent(0, true) // true для получения данных по каждому ядру
-	if err != nil {
-		return fmt.Errorf("failed to get CPU percent: %v", err)
+	s := d.collect(context.Background())
+	d.apply(s)
+	d.render()
+	return s.Err
+}
+
+// apply обновляет виджеты по снимку состояния системы. Ошибка сбора не
+// прерывает работу: она показывается в строке состояния, а индикаторы
+// сохраняют предыдущие значения до следующего удачного снимка.
+func (d *Dashboard) apply(s system.Snapshot) {
+	if s.Err != nil {
+		d.report(severityError, "%v", s.Err)
+		return
 	}
 
-	for i, percent := range cpuPercents {
+	// Обновляем CPU для каждого ядра
+	d.cpuPercents = s.CPU
+	for i, percent := range s.CPU {
 		if i < len(d.cpuCharts) {
 			intPercent := int(percent)
 			d.cpuCharts[i].Percent = intPercent
-			d.cpuCharts[i].BarColor = getColorByPercent(intPercent)
+			d.cpuCharts[i].BarColor = d.colors.colorFor(intPercent)
+			d.cpuHistory[i].history.push(percent)
+			d.cpuHistory[i].Title = fmt.Sprintf("CPU Core %d: %d%%", i, intPercent)
+		}
+	}
+	// Общий индикатор показывает среднюю загрузку ядер
+	if len(s.CPU) > 0 {
+		var total float64
+		for _, percent := range s.CPU {
+			total += percent
 		}
+		average := total / float64(len(s.CPU))
+		d.cpuTotalChart.Percent = int(average)
+		d.cpuTotalChart.BarColor = d.colors.colorFor(int(average))
+		d.cpuTotalHistory.history.push(average)
+		d.cpuTotalHistory.Title = fmt.Sprintf("CPU (%d cores): %d%%", len(s.CPU), int(average))
 	}
 
 	// Обновляем память
-	memInfo, err := mem.VirtualMemory()
-	if err != nil {
-		return fmt.Errorf("failed to get memory info: %v", err)
-	}
+	memInfo := s.Memory
+	d.memInfo = memInfo
 	percent := int(memInfo.UsedPercent)
 	d.memChart.Percent = percent
-	d.memChart.BarColor = getColorByPercent(percent)
-	
+	d.memChart.BarColor = d.colors.colorFor(percent)
+
 	// Обновляем метку с детальной информацией о памяти
 	usedMem := formatBytes(memInfo.Used)
 	totalMem := formatBytes(memInfo.Total)
 	d.memChart.Label = fmt.Sprintf("%d%% [%s / %s]", percent, usedMem, totalMem)
-	
+
 	// Добавляем информацию о свободной памяти в заголовок
 	freeMem := formatBytes(memInfo.Available)
 	d.memChart.Title = fmt.Sprintf("Memory Usage (Free: %s)", freeMem)
+	d.memHistory.history.push(memInfo.UsedPercent)
+	d.memHistory.Title = fmt.Sprintf("Memory Usage: %s (Free: %s)", d.memChart.Label, freeMem)
 
-	// Обновляем список процессов
-	processes, err := system.GetProcessList()
-	if err != nil {
-		log.Printf("failed to get process list: %v", err)
+	// Обновляем список процессов. Снимок не изменяется, поэтому сортировка
+	// выполняется на копии.
+	if s.ProcessErr != nil {
+		d.report(severityError, "Failed to get process list: %v", s.ProcessErr)
 	} else {
-		d.processes = processes // Сохраняем список процессов
-		processTexts := make([]string, 0, len(processes))
-		for _, p := range processes {
-			processTexts = append(processTexts,
-				fmt.Sprintf("[%d] %s (CPU: %.1f%%, Mem: %.1f%%, Status: %s)",
-					p.PID, p.Name, p.CPU, p.Memory, p.Status))
-		}
-		d.processList.Rows = processTexts
-
-		// Сохраняем текущую позицию курсора в пределах списка
-		if d.selectedRow >= len(processTexts) {
-			d.selectedRow = len(processTexts) - 1
+		d.allProcesses = slices.Clone(s.Processes)
+		d.pruneTags()
+		d.refreshProcessList()
+		if d.detail != nil {
+			d.refreshDetail()
 		}
-		d.processList.SelectedRow = d.selectedRow
 	}
-
-	// Рендерим все виджеты
-	drawables := make([]ui.Drawable, 0, len(d.cpuCharts)+3)
-	for _, chart := range d.cpuCharts {
-		drawables = append(drawables, chart)
-	}
-	drawables = append(drawables, d.memChart, d.processList)
-	if d.showSignalMenu {
-		d.updateSignalMenuPosition()
-		drawables = append(drawables, d.signalMenu)
-	}
-	d.ui.Render(drawables...)
-
-	return nil
-} 
\ No newline at end of file
+}
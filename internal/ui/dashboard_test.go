@@ -1,10 +1,15 @@
 package ui
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	ui "github.com/gizak/termui/v3"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/system"
 )
 
 // MockUI реализация UIProvider для тестирования
@@ -294,9 +299,72 @@ func TestDashboard_Update(t *testing.T) {
 	}
 
 	// Проверяем, что все виджеты были отрендерены
-	expectedWidgets := len(dashboard.cpuCharts) + 2 // CPU charts + memory + process list
+	expectedWidgets := len(dashboard.cpuCharts) + 4 // CPU charts + memory + column header + process list + status line
 	if len(mockUI.renderedItems) != expectedWidgets {
 		t.Errorf("Неверное количество отрендеренных виджетов: %d, ожидалось: %d", 
 			len(mockUI.renderedItems), expectedWidgets)
 	}
-} 
\ No newline at end of file
+} 
+
+func TestDashboard_RunQuitsDuringSlowCollection(t *testing.T) {
+	mockUI := NewMockUI()
+	dashboard, err := NewDashboardWithUI(mockUI)
+	if err != nil {
+		t.Fatalf("NewDashboardWithUI() вернул ошибку: %v", err)
+	}
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	dashboard.collect = func(ctx context.Context) system.Snapshot {
+		close(started)
+		<-ctx.Done() // обход процессов "зависает" до отмены
+		close(cancelled)
+		return system.Snapshot{Err: ctx.Err()}
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- dashboard.Run()
+	}()
+	<-started
+
+	// Клавиши обрабатываются, пока идет сбор данных
+	mockUI.events <- ui.Event{Type: ui.KeyboardEvent, ID: "<Down>"}
+	mockUI.events <- ui.Event{Type: ui.KeyboardEvent, ID: "q"}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() вернул ошибку: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("q did not exit while data was being collected")
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("Expected collection to be cancelled on exit")
+	}
+}
+
+func TestDashboard_ApplyDoesNotModifySnapshot(t *testing.T) {
+	dashboard, err := NewDashboardWithConfig(NewMockUI(), config.Default())
+	if err != nil {
+		t.Fatalf("NewDashboardWithConfig() вернул ошибку: %v", err)
+	}
+	dashboard.sortKey, dashboard.sortDesc = SortByPID, true
+	s := system.Snapshot{
+		CPU:       []float64{10},
+		Memory:    &mem.VirtualMemoryStat{Total: 100, Used: 50, UsedPercent: 50},
+		Processes: []system.ProcessInfo{{PID: 1}, {PID: 2}, {PID: 3}},
+	}
+	dashboard.apply(s)
+	if !equalPIDs(pids(dashboard.processes), []int32{3, 2, 1}) {
+		t.Errorf("Expected processes sorted by PID desc, got %v", pids(dashboard.processes))
+	}
+	if !equalPIDs(pids(s.Processes), []int32{1, 2, 3}) {
+		t.Errorf("Snapshot must not be modified, got %v", pids(s.Processes))
+	}
+	if dashboard.memChart.Percent != 50 {
+		t.Errorf("Expected memory gauge 50%%, got %d", dashboard.memChart.Percent)
+	}
+}
@@ -0,0 +1,295 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// detailPane - панель подробных сведений о процессе. Открывается по Enter
+// и обновляется вместе со списком, пока ее не закроют.
+type detailPane struct {
+	process system.ProcessInfo    // последние данные процесса из списка
+	exited  bool                  // процесс завершился или PID занял другой процесс
+	info    *system.ProcessDetail // nil - сведения недоступны, причина в err
+	err     error
+	reading bool          // сведения читаются в фоне
+	cpu     *historyMeter // загрузка CPU с момента открытия панели
+	rss     *historyMeter // резидентная память с момента открытия панели
+	text    *widgets.List
+}
+
+// errNoDetails - источник данных не умеет читать сведения о процессах
+var errNoDetails = errors.New("process details are not available for this data source")
+
+// detailResult - сведения о процессе, прочитанные в фоне для панели pane
+type detailResult struct {
+	pane *detailPane
+	info *system.ProcessDetail
+	err  error
+}
+
+// openDetail открывает панель сведений о выбранном процессе
+func (d *Dashboard) openDetail() {
+	if d.selectedRow >= len(d.processes) {
+		return
+	}
+	period := time.Duration(d.config.Layout.History)
+	pane := &detailPane{
+		process: d.processes[d.selectedRow],
+		cpu:     newHistoryMeter(period, d.refreshInterval, config.MeterSparkline, &d.colors),
+		rss:     newHistoryMeter(period, d.refreshInterval, config.MeterSparkline, nil),
+		text:    widgets.NewList(),
+	}
+	pane.rss.scale = 0 // шкала памяти подстраивается под максимум
+	pane.rss.color = ui.ColorCyan
+	pane.text.TextStyle = ui.NewStyle(ui.ColorWhite)
+	pane.text.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorWhite)
+	pane.text.WrapText = false
+	if d.options.Monochrome {
+		plain := ui.NewStyle(ui.ColorClear)
+		pane.rss.color = ui.ColorWhite
+		for _, block := range []*ui.Block{&pane.cpu.Block, &pane.rss.Block, &pane.text.Block} {
+			block.BorderStyle = plain
+			block.TitleStyle = plain
+		}
+		pane.text.TextStyle = plain
+		pane.text.SelectedRowStyle = ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierReverse)
+	}
+
+	d.detail = pane
+	d.pushDetailHistory()
+	d.readDetail()
+	d.updateDetailLayout()
+}
+
+// closeDetail закрывает панель сведений о процессе
+func (d *Dashboard) closeDetail() {
+	d.detail = nil
+}
+
+// handleDetailKey обрабатывает клавиши на панели сведений о процессе
+func (d *Dashboard) handleDetailKey(id string) {
+	text := d.detail.text
+	switch id {
+	case "<Escape>", "<Enter>", "<Left>", "q":
+		d.closeDetail()
+	case "<Up>":
+		text.ScrollUp()
+	case "<Down>":
+		text.ScrollDown()
+	case "<PageUp>":
+		text.ScrollPageUp()
+	case "<PageDown>":
+		text.ScrollPageDown()
+	case "<Home>":
+		text.ScrollTop()
+	case "<End>":
+		text.ScrollBottom()
+	}
+}
+
+// refreshDetail обновляет панель после нового замера: дополняет историю
+// и перечитывает сведения о процессе
+func (d *Dashboard) refreshDetail() {
+	pane := d.detail
+	if pane.exited {
+		return
+	}
+	for _, p := range d.allProcesses {
+		// Совпадение PID без совпадения времени запуска - уже другой процесс
+		if p.PID == pane.process.PID && p.StartTime.Equal(pane.process.StartTime) {
+			pane.process = p
+			d.pushDetailHistory()
+			d.readDetail()
+			return
+		}
+	}
+	pane.exited = true
+	d.updateDetailText()
+}
+
+// pushDetailHistory добавляет текущие CPU и память процесса в историю панели
+func (d *Dashboard) pushDetailHistory() {
+	pane := d.detail
+	pane.cpu.history.push(pane.process.CPU)
+	pane.rss.history.push(float64(pane.process.RSS))
+	pane.cpu.Title = fmt.Sprintf("CPU %.1f%%", pane.process.CPU)
+	pane.rss.Title = fmt.Sprintf("RSS %s", formatBytes(pane.process.RSS))
+}
+
+// readDetail запускает чтение сведений о процессе в фоне: командная строка,
+// окружение и лимиты читаются несколькими обращениями к системе, и интерфейс
+// не должен их ждать. Пока чтение не закончилось, новое не начинается, а
+// панель показывает прежние сведения. Результат принимает Run.
+func (d *Dashboard) readDetail() {
+	pane := d.detail
+	source, ok := d.source.(system.DetailSource)
+	if !ok {
+		pane.info, pane.err = nil, errNoDetails
+		d.updateDetailText()
+		return
+	}
+	d.updateDetailText()
+	if pane.reading {
+		return
+	}
+	pane.reading = true
+	ctx, pid := d.ctx, pane.process.PID
+	go func() {
+		info, err := source.ProcessDetail(ctx, pid)
+		select {
+		case d.details <- detailResult{pane: pane, info: info, err: err}:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// applyDetail показывает сведения, прочитанные в фоне. Результат для уже
+// закрытой панели отбрасывается, а ошибка чтения после завершения процесса
+// не заменяет сведения, прочитанные раньше.
+func (d *Dashboard) applyDetail(r detailResult) {
+	pane := r.pane
+	pane.reading = false
+	if pane != d.detail || pane.exited && r.err != nil && pane.info != nil {
+		return
+	}
+	pane.info, pane.err = r.info, r.err
+	d.updateDetailText()
+}
+
+// updateDetailText перестраивает строки панели, сохраняя позицию прокрутки
+func (d *Dashboard) updateDetailText() {
+	pane := d.detail
+	p := pane.process
+
+	title := fmt.Sprintf("Process %d: %s", p.PID, p.Name)
+	if pane.exited {
+		title += " [exited]"
+	}
+	pane.text.Title = title + " (↑/↓ scroll, Esc close)"
+
+	field := func(name, value string) string {
+		return fmt.Sprintf("%-12s %s", name+":", value)
+	}
+	var rows []string
+	info := pane.info
+	if info != nil && len(info.Args) > 0 {
+		rows = append(rows, field("Command", sanitize(strings.Join(info.Args, " "))))
+	} else {
+		rows = append(rows, field("Command", processCommand(p)))
+	}
+	if info == nil {
+		rows = append(rows, field("Owner", p.User))
+	} else {
+		owner := fmt.Sprintf("%s (uid %d)", info.User, info.UID)
+		if info.EUID != info.UID {
+			owner = fmt.Sprintf("%s (uid %d, euid %d)", info.User, info.UID, info.EUID)
+		}
+		rows = append(rows,
+			field("Owner", owner),
+			field("Executable", detailValue(info.Exe, info.Errors["exe"])),
+			field("Working dir", detailValue(info.Cwd, info.Errors["cwd"])),
+		)
+	}
+	rows = append(rows,
+		field("State", fmt.Sprintf("%s, %d threads, priority %d, nice %d", statusChar(p.Status), p.Threads, p.Priority, p.Nice)),
+		field("Started", fmt.Sprintf("%s, CPU time %s", formatStartDateTime(p.StartTime), formatCPUTime(p.CPUTime))),
+		field("Memory", fmt.Sprintf("RSS %s, VIRT %s, %.1f%%", formatBytes(p.RSS), formatBytes(p.VSZ), p.Memory)),
+	)
+	if p.Cgroup != "" {
+		rows = append(rows, field("Cgroup", p.Cgroup))
+	}
+
+	switch {
+	case info == nil && pane.err == nil:
+		rows = append(rows, "", "Reading process details...")
+	case info == nil:
+		rows = append(rows, "", pane.err.Error())
+	default:
+		rows = append(rows, "", fmt.Sprintf("%-26s %-12s %-12s %s", "Limits:", "Soft", "Hard", "Units"))
+		if err := info.Errors["limits"]; err != nil {
+			rows = append(rows, "  "+describeError(err))
+		}
+		for _, l := range info.Limits {
+			rows = append(rows, fmt.Sprintf("  %-24s %-12s %-12s %s", l.Name, l.Soft, l.Hard, l.Units))
+		}
+
+		rows = append(rows, "", fmt.Sprintf("Environment (%d):", len(info.Environ)))
+		if err := info.Errors["environ"]; err != nil {
+			rows = append(rows, "  "+describeError(err))
+		}
+		for _, v := range info.Environ {
+			rows = append(rows, "  "+sanitize(v))
+		}
+	}
+
+	pane.text.Rows = rows
+	if pane.text.SelectedRow >= len(rows) {
+		pane.text.SelectedRow = len(rows) - 1
+	}
+}
+
+// updateDetailLayout располагает панель поверх списка процессов: графики
+// CPU и памяти сверху, сведения под ними
+func (d *Dashboard) updateDetailLayout() {
+	if d.detail == nil {
+		return
+	}
+	rect := d.processList.GetRect()
+	top := rect.Min.Y
+	// На низком экране графики не помещаются - остаются только сведения
+	if rect.Dy() >= 2*gaugeHeight {
+		middle := rect.Min.X + rect.Dx()/2
+		d.detail.cpu.SetRect(rect.Min.X, top, middle, top+gaugeHeight)
+		d.detail.rss.SetRect(middle, top, rect.Max.X, top+gaugeHeight)
+		top += gaugeHeight
+	} else {
+		d.detail.cpu.SetRect(0, 0, 0, 0)
+		d.detail.rss.SetRect(0, 0, 0, 0)
+	}
+	d.detail.text.SetRect(rect.Min.X, top, rect.Max.X, rect.Max.Y)
+}
+
+// detailDrawables возвращает виджеты панели сведений
+func (d *Dashboard) detailDrawables() []ui.Drawable {
+	if d.detail.cpu.GetRect().Empty() {
+		return []ui.Drawable{d.detail.text}
+	}
+	return []ui.Drawable{d.detail.cpu, d.detail.rss, d.detail.text}
+}
+
+// detailValue возвращает значение поля или причину, по которой его нет
+func detailValue(value string, err error) string {
+	if err != nil {
+		return describeError(err)
+	}
+	return value
+}
+
+// describeError кратко описывает ошибку чтения сведений о процессе
+func describeError(err error) string {
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return "unavailable: permission denied"
+	case errors.Is(err, fs.ErrNotExist):
+		return "unavailable"
+	}
+	return "unavailable: " + err.Error()
+}
+
+// formatStartDateTime форматирует полное время запуска процесса
+func formatStartDateTime(start time.Time) string {
+	if start.IsZero() {
+		return "-"
+	}
+	return start.Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/record"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// newDetailDashboard создает Dashboard с фиктивным источником, в котором
+// для PID 200 есть подробные сведения, и выбранным PID 200
+func newDetailDashboard(t *testing.T) (*Dashboard, *fakeSource) {
+	t.Helper()
+	source := newFakeSource()
+	source.processes[1].StartTime = time.Date(2024, 5, 1, 9, 30, 0, 0, time.Local)
+	source.processes[1].RSS = 64 << 20
+	source.details = map[int32]*system.ProcessDetail{
+		200: {
+			PID:     200,
+			Args:    []string{"/usr/bin/worker", "--queue", "jobs\nnext"},
+			Exe:     "/usr/bin/worker",
+			UID:     1000,
+			EUID:    0,
+			User:    "alice",
+			Environ: []string{"HOME=/home/alice"},
+			Limits:  []system.Limit{{Name: "Max open files", Soft: "1024", Hard: "4096", Units: "files"}},
+			Errors:  map[string]error{"cwd": &fs.PathError{Op: "readlink", Path: "/proc/200/cwd", Err: fs.ErrPermission}},
+		},
+	}
+	dashboard, err := NewDashboardWithSource(NewMockUI(), source, config.Default())
+	if err != nil {
+		t.Fatalf("NewDashboardWithSource() вернул ошибку: %v", err)
+	}
+	if err := dashboard.update(); err != nil {
+		t.Fatalf("update() вернул ошибку: %v", err)
+	}
+	if dashboard.processes[0].PID != 200 {
+		t.Fatalf("Expected PID 200 selected, got %v", pids(dashboard.processes))
+	}
+	return dashboard, source
+}
+
+// waitDetail принимает сведения, прочитанные в фоне, как это делает Run
+func waitDetail(t *testing.T, dashboard *Dashboard) {
+	t.Helper()
+	select {
+	case r := <-dashboard.details:
+		dashboard.applyDetail(r)
+	case <-time.After(time.Second):
+		t.Fatal("Expected process details to be read")
+	}
+}
+
+func TestDashboard_DetailPane(t *testing.T) {
+	dashboard, _ := newDetailDashboard(t)
+	dashboard.handleKey("<Enter>")
+	if dashboard.detail == nil {
+		t.Fatal("Expected Enter to open the detail pane")
+	}
+	waitDetail(t, dashboard)
+
+	text := strings.Join(dashboard.detail.text.Rows, "\n")
+	for _, want := range []string{
+		"Command:     /usr/bin/worker --queue jobs next",
+		"Owner:       alice (uid 1000, euid 0)",
+		"Executable:  /usr/bin/worker",
+		"Working dir: unavailable: permission denied",
+		"Started:     2024-05-01 09:30:00",
+		"  Max open files           1024         4096         files",
+		"Environment (1):\n  HOME=/home/alice",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected detail pane to contain %q, got:\n%s", want, text)
+		}
+	}
+	if title := dashboard.detail.text.Title; !strings.HasPrefix(title, "Process 200: worker") {
+		t.Errorf("Unexpected detail title %q", title)
+	}
+
+	dashboard.render()
+	rendered := dashboard.ui.(*MockUI).renderedItems
+	if rendered[len(rendered)-1] != dashboard.detail.text {
+		t.Error("Expected detail pane to be drawn over the process list")
+	}
+
+	// Клавиши списка не действуют, пока панель открыта
+	dashboard.handleKey("<Down>")
+	if dashboard.selectedRow != 0 || dashboard.detail.text.SelectedRow != 1 {
+		t.Errorf("Expected Down to scroll the pane, got row %d, pane row %d",
+			dashboard.selectedRow, dashboard.detail.text.SelectedRow)
+	}
+	dashboard.handleKey("<Escape>")
+	if dashboard.detail != nil {
+		t.Error("Expected Escape to close the detail pane")
+	}
+}
+
+func TestDashboard_DetailPaneUpdatesLive(t *testing.T) {
+	dashboard, source := newDetailDashboard(t)
+	dashboard.handleKey("<Enter>")
+	waitDetail(t, dashboard)
+
+	for _, cpu := range []float64{20, 150} {
+		source.processes[1].CPU = cpu
+		if err := dashboard.update(); err != nil {
+			t.Fatalf("update() вернул ошибку: %v", err)
+		}
+		waitDetail(t, dashboard)
+	}
+	pane := dashboard.detail
+	if got := pane.cpu.history.values(); !equalFloats(got, []float64{80, 20, 150}) {
+		t.Errorf("Expected CPU history since selection, got %v", got)
+	}
+	if got := pane.rss.history.values(); len(got) != 3 || got[2] != 64<<20 {
+		t.Errorf("Expected RSS history since selection, got %v", got)
+	}
+	if pane.cpu.Title != "CPU 150.0%" || pane.rss.Title != "RSS 64.0 MiB" {
+		t.Errorf("Unexpected meter titles %q, %q", pane.cpu.Title, pane.rss.Title)
+	}
+
+	// PID занял другой процесс - панель показывает, что исходный завершился
+	source.processes[1].StartTime = source.processes[1].StartTime.Add(time.Hour)
+	if err := dashboard.update(); err != nil {
+		t.Fatalf("update() вернул ошибку: %v", err)
+	}
+	if !pane.exited || !strings.Contains(pane.text.Title, "[exited]") {
+		t.Errorf("Expected pane to show the process as exited, got %q", pane.text.Title)
+	}
+	if got := len(pane.cpu.history.values()); got != 3 {
+		t.Errorf("Expected history to stop after exit, got %d values", got)
+	}
+}
+
+func TestDashboard_DetailPaneWithoutDetails(t *testing.T) {
+	dashboard, err := NewDashboardWithSource(NewMockUI(), record.NewPlayer(replayFrames(2)), config.Default())
+	if err != nil {
+		t.Fatalf("NewDashboardWithSource() вернул ошибку: %v", err)
+	}
+	dashboard.startReplay()
+	defer dashboard.replayTimer.Stop()
+
+	dashboard.handleKey("<Enter>")
+	if dashboard.detail == nil {
+		t.Fatal("Expected detail pane in replay mode")
+	}
+	text := strings.Join(dashboard.detail.text.Rows, "\n")
+	if !strings.Contains(text, errNoDetails.Error()) || !strings.Contains(text, fmt.Sprintf("Command:     %s", dashboard.detail.process.Name)) {
+		t.Errorf("Expected recorded data and a note about missing details, got:\n%s", text)
+	}
+}
+
+func TestDashboard_DetailReadInBackground(t *testing.T) {
+	dashboard, source := newDetailDashboard(t)
+	source.detailGate = make(chan struct{})
+	dashboard.handleKey("<Enter>")
+	if text := strings.Join(dashboard.detail.text.Rows, "\n"); !strings.Contains(text, "Reading process details...") {
+		t.Errorf("Expected the pane to open before details are read, got:\n%s", text)
+	}
+
+	for deadline := time.Now().Add(time.Second); source.detailReads.Load() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected Enter to start a detail read")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Пока чтение не закончилось, обновления не начинают новое
+	for i := 0; i < 3; i++ {
+		if err := dashboard.update(); err != nil {
+			t.Fatalf("update() вернул ошибку: %v", err)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := source.detailReads.Load(); n != 1 {
+		t.Errorf("Expected a single detail read in flight, got %d", n)
+	}
+
+	// Сведения закрытой панели не попадают в новую
+	pane := dashboard.detail
+	dashboard.handleKey("<Escape>")
+	close(source.detailGate)
+	waitDetail(t, dashboard)
+	if pane.info != nil || dashboard.detail != nil {
+		t.Error("Expected details of a closed pane to be dropped")
+	}
+}
+
+func TestDashboard_RunQuitsDuringDetailRead(t *testing.T) {
+	dashboard, source := newDetailDashboard(t)
+	source.detailGate = make(chan struct{})
+	mockUI := dashboard.ui.(*MockUI)
+
+	done := make(chan error)
+	go func() {
+		done <- dashboard.Run()
+	}()
+	mockUI.events <- ui.Event{Type: ui.KeyboardEvent, ID: "<Enter>"}
+	// Чтение сведений висит, но клавиши обрабатываются
+	mockUI.events <- ui.Event{Type: ui.KeyboardEvent, ID: "<Down>"}
+	mockUI.events <- ui.Event{Type: ui.KeyboardEvent, ID: "<Escape>"}
+	mockUI.events <- ui.Event{Type: ui.KeyboardEvent, ID: "q"}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() вернул ошибку: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("q did not exit while process details were being read")
+	}
+}
+
+func TestDashboard_DetailPaneLayout(t *testing.T) {
+	dashboard, _ := newDetailDashboard(t)
+	dashboard.handleKey("<Enter>")
+	waitDetail(t, dashboard)
+	list := dashboard.processList.GetRect()
+
+	if rect := dashboard.detail.text.GetRect(); rect.Max != list.Max || rect.Min.Y != list.Min.Y+gaugeHeight {
+		t.Errorf("Expected pane text under the graphs inside %v, got %v", list, rect)
+	}
+
+	dashboard.handleResize(80, list.Min.Y+4)
+	if len(dashboard.detailDrawables()) != 1 {
+		t.Error("Expected graphs to be hidden on a short screen")
+	}
+}
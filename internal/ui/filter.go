@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// inputMode определяет, куда направляется ввод с клавиатуры
+type inputMode int
+
+const (
+	inputNone   inputMode = iota
+	inputSearch           // инкрементальный поиск по '/'
+	inputFilter           // фильтр списка по '\'
+)
+
+// processMatches проверяет, подходит ли процесс под запрос.
+// Сравнение регистронезависимое по имени, командной строке и PID.
+func processMatches(p system.ProcessInfo, query string) bool {
+	if query == "" {
+		return true
+	}
+	query = strings.ToLower(query)
+	return strings.Contains(strings.ToLower(p.Name), query) ||
+		strings.Contains(strings.ToLower(p.Cmdline), query) ||
+		strings.Contains(strconv.Itoa(int(p.PID)), query)
+}
+
+// filterProcesses возвращает процессы, подходящие под запрос и дополнительное
+// условие match (если оно задано)
+func filterProcesses(processes []system.ProcessInfo, query string, match func(system.ProcessInfo) bool) []system.ProcessInfo {
+	if query == "" && match == nil {
+		return processes
+	}
+	result := make([]system.ProcessInfo, 0, len(processes))
+	for _, p := range processes {
+		if processMatches(p, query) && (match == nil || match(p)) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// findMatch ищет первую подходящую строку начиная с from, продолжая поиск
+// с начала списка. Возвращает -1, если совпадений нет.
+func findMatch(processes []system.ProcessInfo, query string, from int) int {
+	n := len(processes)
+	if n == 0 || query == "" {
+		return -1
+	}
+	if from < 0 || from >= n {
+		from = 0
+	}
+	for i := 0; i < n; i++ {
+		idx := (from + i) % n
+		if processMatches(processes[idx], query) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// inputChar преобразует идентификатор клавиши termui в вводимый текст
+func inputChar(id string) (string, bool) {
+	if id == "<Space>" {
+		return " ", true
+	}
+	if utf8.RuneCountInString(id) == 1 {
+		return id, true
+	}
+	return "", false
+}
+
+// startInput включает режим ввода строки поиска или фильтра
+func (d *Dashboard) startInput(mode inputMode) {
+	d.inputMode = mode
+	d.inputText = ""
+	if mode == inputFilter {
+		d.inputText = d.filterText
+	}
+	d.searchFailed = false
+	d.updateProcessListTitle()
+}
+
+// handleInputKey обрабатывает нажатия клавиш в режиме ввода поиска или фильтра
+func (d *Dashboard) handleInputKey(id string) {
+	switch id {
+	case "<Enter>":
+		d.inputMode = inputNone
+		if d.inputText == "" {
+			d.searchText = ""
+		}
+	case "<Escape>":
+		if d.inputMode == inputFilter {
+			d.filterText = ""
+			d.refreshProcessList()
+		}
+		d.inputMode = inputNone
+		d.searchText = ""
+	case "<Backspace>", "<C-<Backspace>>":
+		if d.inputText != "" {
+			_, size := utf8.DecodeLastRuneInString(d.inputText)
+			d.inputText = d.inputText[:len(d.inputText)-size]
+			d.applyInput()
+		}
+	case "<F3>":
+		if d.inputMode == inputSearch {
+			d.searchNext()
+		}
+	default:
+		if ch, ok := inputChar(id); ok {
+			d.inputText += ch
+			d.applyInput()
+		}
+	}
+	d.updateProcessListTitle()
+}
+
+// applyInput применяет введенный текст: перестраивает фильтр
+// или переходит к ближайшему совпадению поиска
+func (d *Dashboard) applyInput() {
+	switch d.inputMode {
+	case inputFilter:
+		d.filterText = d.inputText
+		d.refreshProcessList()
+	case inputSearch:
+		d.searchText = d.inputText
+		d.jumpToMatch(d.selectedRow)
+	}
+}
+
+// searchNext переходит к следующему совпадению текущего поиска
+func (d *Dashboard) searchNext() {
+	d.jumpToMatch(d.selectedRow + 1)
+}
+
+// jumpToMatch перемещает курсор на ближайшее совпадение начиная со строки from
+func (d *Dashboard) jumpToMatch(from int) {
+	if d.searchText == "" {
+		d.searchFailed = false
+		return
+	}
+	row := findMatch(d.processes, d.searchText, from)
+	d.searchFailed = row < 0
+	if row >= 0 {
+		d.selectRow(row)
+		d.processList.SelectedRow = d.selectedRow
+	}
+}
@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+func TestProcessMatches(t *testing.T) {
+	p := system.ProcessInfo{PID: 1234, Name: "nginx", Cmdline: "/usr/sbin/nginx -g daemon off;"}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected bool
+	}{
+		{"Empty query", "", true},
+		{"Name", "ngin", true},
+		{"Name ignores case", "NGINX", true},
+		{"Command line", "daemon off", true},
+		{"PID", "23", true},
+		{"No match", "postgres", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := processMatches(p, tt.query); got != tt.expected {
+				t.Errorf("processMatches(%q) = %v, want %v", tt.query, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindMatch(t *testing.T) {
+	processes := []system.ProcessInfo{
+		{PID: 1, Name: "init"},
+		{PID: 2, Name: "bash"},
+		{PID: 3, Name: "vim"},
+		{PID: 4, Name: "bash"},
+	}
+
+	if got := findMatch(processes, "bash", 0); got != 1 {
+		t.Errorf("Expected first match at 1, got %d", got)
+	}
+	if got := findMatch(processes, "bash", 2); got != 3 {
+		t.Errorf("Expected next match at 3, got %d", got)
+	}
+	if got := findMatch(processes, "bash", 4); got != 1 {
+		t.Errorf("Expected search to wrap around to 1, got %d", got)
+	}
+	if got := findMatch(processes, "zsh", 0); got != -1 {
+		t.Errorf("Expected no match, got %d", got)
+	}
+}
+
+func typeText(d *Dashboard, text string) {
+	for _, r := range text {
+		d.handleKey(string(r))
+	}
+}
+
+func TestDashboard_Search(t *testing.T) {
+	dashboard, err := NewDashboardWithUI(NewMockUI())
+	if err != nil {
+		t.Fatalf("Failed to create dashboard: %v", err)
+	}
+	dashboard.allProcesses = testProcesses()
+	dashboard.refreshProcessList()
+
+	dashboard.handleKey("/")
+	if dashboard.inputMode != inputSearch {
+		t.Fatal("Expected '/' to start search")
+	}
+
+	// Во время ввода горячие клавиши сортировки не срабатывают
+	typeText(dashboard, "Xo")
+	if dashboard.sortKey != SortByCPU {
+		t.Errorf("Expected typed text not to change sort mode, got %v", dashboard.sortKey)
+	}
+	if got := dashboard.processes[dashboard.selectedRow].PID; got != 20 {
+		t.Errorf("Expected cursor on Xorg (PID 20), got %d", got)
+	}
+	if !strings.Contains(dashboard.processList.Title, "Search: Xo") {
+		t.Errorf("Expected search text in title, got %q", dashboard.processList.Title)
+	}
+
+	typeText(dashboard, "zz")
+	if !dashboard.searchFailed {
+		t.Error("Expected search to fail for unknown text")
+	}
+	dashboard.handleKey("<Backspace>")
+	dashboard.handleKey("<Backspace>")
+	dashboard.handleKey("<Enter>")
+	if dashboard.inputMode != inputNone {
+		t.Error("Expected Enter to leave search mode")
+	}
+	if len(dashboard.processes) != len(testProcesses()) {
+		t.Error("Search must not hide processes")
+	}
+}
+
+func TestDashboard_SearchNext(t *testing.T) {
+	dashboard, err := NewDashboardWithUI(NewMockUI())
+	if err != nil {
+		t.Fatalf("Failed to create dashboard: %v", err)
+	}
+	dashboard.allProcesses = testProcesses()
+	dashboard.refreshProcessList()
+
+	// "0" совпадает с PID 30, 20 и 10 (по убыванию CPU: 30, 20, 10, 5)
+	dashboard.handleKey("/")
+	typeText(dashboard, "0")
+	dashboard.handleKey("<Enter>")
+	if dashboard.selectedRow != 0 {
+		t.Fatalf("Expected first match at row 0, got %d", dashboard.selectedRow)
+	}
+	dashboard.handleKey("<F3>")
+	if dashboard.selectedRow != 1 {
+		t.Errorf("Expected F3 to jump to row 1, got %d", dashboard.selectedRow)
+	}
+	dashboard.handleKey("<F3>")
+	dashboard.handleKey("<F3>")
+	if dashboard.selectedRow != 0 {
+		t.Errorf("Expected search to wrap to row 0, got %d", dashboard.selectedRow)
+	}
+}
+
+func TestDashboard_Filter(t *testing.T) {
+	dashboard, err := NewDashboardWithUI(NewMockUI())
+	if err != nil {
+		t.Fatalf("Failed to create dashboard: %v", err)
+	}
+	dashboard.allProcesses = testProcesses()
+	dashboard.refreshProcessList()
+
+	dashboard.handleKey("\\")
+	if dashboard.inputMode != inputFilter {
+		t.Fatal("Expected '\\' to start filter input")
+	}
+	typeText(dashboard, "b")
+	// Фильтр применяется сразу: "b" есть только в bash
+	if len(dashboard.processes) != 1 || dashboard.processes[0].PID != 10 {
+		t.Fatalf("Expected only bash to be visible, got %v", pids(dashboard.processes))
+	}
+	if len(dashboard.processList.Rows) != 1 {
+		t.Errorf("Expected 1 row in list, got %d", len(dashboard.processList.Rows))
+	}
+
+	dashboard.handleKey("<Enter>")
+	if !strings.Contains(dashboard.processList.Title, "filter: b, 1/4") {
+		t.Errorf("Expected filter state in title, got %q", dashboard.processList.Title)
+	}
+
+	// Фильтр сохраняется при обновлении данных
+	dashboard.allProcesses = testProcesses()
+	dashboard.refreshProcessList()
+	if len(dashboard.processes) != 1 {
+		t.Errorf("Expected filter to survive refresh, got %d processes", len(dashboard.processes))
+	}
+
+	dashboard.handleKey("<Escape>")
+	if dashboard.filterText != "" || len(dashboard.processes) != 4 {
+		t.Errorf("Expected Escape to clear filter, got %q and %d processes",
+			dashboard.filterText, len(dashboard.processes))
+	}
+	if strings.Contains(dashboard.processList.Title, "filter:") {
+		t.Errorf("Expected no filter in title, got %q", dashboard.processList.Title)
+	}
+}
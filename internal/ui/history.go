@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"image"
+	"slices"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+
+	"github.com/bonefabric/htop/internal/config"
+)
+
+// maxHistorySamples - размер кольцевого буфера истории. Этого хватает на
+// ширину любого терминала, а память не зависит от периода и интервала обновления.
+const maxHistorySamples = 1024
+
+// history - значения за последний период в кольцевом буфере фиксированного
+// размера. Если за период собирается больше maxHistorySamples замеров,
+// соседние замеры объединяются по максимуму, чтобы короткие пики не терялись.
+type history struct {
+	samples []float64
+	start   int     // индекс самого старого значения
+	count   int     // количество значений в буфере
+	step    int     // замеров в одном значении буфера
+	pending int     // замеров в незавершенном значении
+	peak    float64 // максимум незавершенного значения
+}
+
+// newHistory создает историю за period при замерах каждые interval
+func newHistory(period, interval time.Duration) *history {
+	n := 2
+	if interval > 0 && int(period/interval) > n {
+		n = int(period / interval)
+	}
+	step := (n + maxHistorySamples - 1) / maxHistorySamples // округление вверх
+	return &history{samples: make([]float64, (n+step-1)/step), step: step}
+}
+
+// push добавляет замер, вытесняя самое старое значение при заполнении буфера
+func (h *history) push(v float64) {
+	if h.pending == 0 || v > h.peak {
+		h.peak = v
+	}
+	h.pending++
+	if h.pending < h.step {
+		return
+	}
+	h.pending = 0
+
+	if h.count < len(h.samples) {
+		h.samples[(h.start+h.count)%len(h.samples)] = h.peak
+		h.count++
+		return
+	}
+	h.samples[h.start] = h.peak
+	h.start = (h.start + 1) % len(h.samples)
+}
+
+// values возвращает значения от самого старого к самому новому
+func (h *history) values() []float64 {
+	values := make([]float64, h.count)
+	for i := range values {
+		values[i] = h.samples[(h.start+i)%len(h.samples)]
+	}
+	return values
+}
+
+// reset удаляет все значения
+func (h *history) reset() {
+	h.start, h.count, h.pending = 0, 0, 0
+}
+
+// resample сжимает values до width значений, беря максимум каждой группы
+// соседних значений. Если значений не больше width, они возвращаются как есть.
+func resample(values []float64, width int) []float64 {
+	if width <= 0 {
+		return nil
+	}
+	if len(values) <= width {
+		return values
+	}
+	out := make([]float64, width)
+	for i := range out {
+		// Границы групп распределяются равномерно, последняя группа включает самое новое значение
+		from, to := i*len(values)/width, (i+1)*len(values)/width
+		out[i] = values[from]
+		for _, v := range values[from+1 : to] {
+			if v > out[i] {
+				out[i] = v
+			}
+		}
+	}
+	return out
+}
+
+// sparkBars - символы спарклайна по восьмым долям высоты строки
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// historyMeter - индикатор, показывающий историю значений спарклайном
+// или графиком. Самое новое значение рисуется у правого края.
+type historyMeter struct {
+	ui.Block
+	history *history
+	style   string
+	scale   float64      // значение во всю высоту; шкала растет, если значения больше
+	colors  *colorScheme // цвет по проценту шкалы, nil - всегда color
+	color   ui.Color
+}
+
+// newHistoryMeter создает индикатор с историей за period
+func newHistoryMeter(period, interval time.Duration, style string, colors *colorScheme) *historyMeter {
+	m := &historyMeter{
+		Block:   *ui.NewBlock(),
+		history: newHistory(period, interval),
+		style:   style,
+		scale:   100,
+		colors:  colors,
+	}
+	m.BorderStyle.Fg = ui.ColorCyan
+	m.TitleStyle.Fg = ui.ColorWhite
+	return m
+}
+
+// Draw рисует рамку и историю в выбранном виде
+func (m *historyMeter) Draw(buf *ui.Buffer) {
+	m.Block.Draw(buf)
+	values := resample(m.history.values(), m.Inner.Dx())
+	full := m.scale
+	for _, v := range values {
+		if v > full {
+			full = v
+		}
+	}
+	if full <= 0 {
+		full = 1
+	}
+	percents := make([]float64, len(values))
+	for i, v := range values {
+		percents[i] = v / full * 100
+	}
+
+	left := m.Inner.Max.X - len(percents)
+	if m.style == config.MeterChart {
+		m.drawChart(buf, percents, left)
+	} else {
+		m.drawSparkline(buf, percents, left)
+	}
+}
+
+// colorFor возвращает цвет значения в процентах шкалы
+func (m *historyMeter) colorFor(percent float64) ui.Color {
+	if m.colors == nil {
+		return m.color
+	}
+	return m.colors.colorFor(int(percent))
+}
+
+// drawSparkline рисует каждое значение в процентах шкалы столбцом из символов sparkBars
+// на всю высоту индикатора
+func (m *historyMeter) drawSparkline(buf *ui.Buffer, values []float64, left int) {
+	rows := m.Inner.Dy()
+	for i, v := range values {
+		// Высота столбца в восьмых долях строки, нулевое значение видно как ▁
+		eighths := int(clampPercent(v) / 100 * float64(rows*8))
+		if eighths < 1 {
+			eighths = 1
+		}
+		style := ui.NewStyle(m.colorFor(v))
+		for row := 0; row < rows && eighths > 0; row++ {
+			bar := sparkBars[len(sparkBars)-1]
+			if eighths < 8 {
+				bar = sparkBars[eighths-1]
+			}
+			buf.SetCell(ui.NewCell(bar, style), image.Pt(left+i, m.Inner.Max.Y-1-row))
+			eighths -= 8
+		}
+	}
+}
+
+// drawChart рисует значения в процентах шкалы линейным графиком шрифтом Брайля:
+// в символе 2 точки по ширине и 4 по высоте
+func (m *historyMeter) drawChart(buf *ui.Buffer, values []float64, left int) {
+	canvas := ui.NewCanvas()
+	canvas.Rectangle = m.Inner
+	dots := m.Inner.Dy()*4 - 1
+	point := func(i int) image.Point {
+		return image.Pt((left+i)*2, m.Inner.Min.Y*4+dots-int(clampPercent(values[i])/100*float64(dots)))
+	}
+	for i := range values {
+		color := m.colorFor(values[i])
+		if i == 0 {
+			canvas.SetPoint(point(i), color)
+		} else {
+			canvas.SetLine(point(i-1), point(i), color)
+		}
+	}
+	canvas.Draw(buf)
+}
+
+// clampPercent ограничивает значение диапазоном 0-100
+func clampPercent(v float64) float64 {
+	switch {
+	case v < 0:
+		return 0
+	case v > 100:
+		return 100
+	}
+	return v
+}
+
+// nextMeterStyle переключает вид индикаторов CPU и памяти по кругу
+// и сохраняет его в настройках
+func (d *Dashboard) nextMeterStyle() {
+	i := slices.Index(config.MeterStyles, d.config.Layout.MeterStyle)
+	style := config.MeterStyles[(i+1)%len(config.MeterStyles)]
+	d.config.Layout.MeterStyle = style
+	for _, m := range d.cpuHistory {
+		m.style = style
+	}
+	d.cpuTotalHistory.style = style
+	d.memHistory.style = style
+
+	// Высота индикаторов зависит от вида, поэтому геометрия пересчитывается
+	d.resize(d.width, d.height)
+	if t, ok := d.ui.(Terminal); ok {
+		t.Clear()
+	}
+	d.saveConfig()
+}
+
+// resetHistory очищает историю индикаторов, например при переходе по записи
+func (d *Dashboard) resetHistory() {
+	for _, m := range d.cpuHistory {
+		m.history.reset()
+	}
+	d.cpuTotalHistory.history.reset()
+	d.memHistory.history.reset()
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"image"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+func TestHistory_Ring(t *testing.T) {
+	h := newHistory(3*time.Second, time.Second)
+	if got := h.values(); len(got) != 0 {
+		t.Errorf("Expected empty history, got %v", got)
+	}
+	for _, v := range []float64{1, 2, 3, 4, 5} {
+		h.push(v)
+	}
+	if got := h.values(); !equalFloats(got, []float64{3, 4, 5}) {
+		t.Errorf("Expected last 3 values, got %v", got)
+	}
+	h.reset()
+	h.push(7)
+	if got := h.values(); !equalFloats(got, []float64{7}) {
+		t.Errorf("Expected history to restart after reset, got %v", got)
+	}
+}
+
+func TestHistory_MergesSamplesOverCapacity(t *testing.T) {
+	// 3000 замеров за период не помещаются в буфер - объединяются по 3
+	h := newHistory(3000*time.Second, time.Second)
+	if len(h.samples) > maxHistorySamples || h.step != 3 {
+		t.Fatalf("Expected bounded buffer with step 3, got %d values, step %d", len(h.samples), h.step)
+	}
+	for _, v := range []float64{10, 90, 20, 5, 5, 5, 1} {
+		h.push(v)
+	}
+	// Короткий пик 90 сохраняется, незавершенная группа еще не видна
+	if got := h.values(); !equalFloats(got, []float64{90, 5}) {
+		t.Errorf("Expected peaks of each group, got %v", got)
+	}
+}
+
+func TestResample(t *testing.T) {
+	values := []float64{1, 9, 2, 2, 3, 8}
+	if got := resample(values, 10); !equalFloats(got, values) {
+		t.Errorf("Expected values to fit as is, got %v", got)
+	}
+	if got := resample(values, 3); !equalFloats(got, []float64{9, 2, 8}) {
+		t.Errorf("Expected group maximums, got %v", got)
+	}
+	if got := resample(values, 0); len(got) != 0 {
+		t.Errorf("Expected nothing for zero width, got %v", got)
+	}
+}
+
+func TestHistoryMeter_DrawSparkline(t *testing.T) {
+	colors := defaultColors
+	m := newHistoryMeter(time.Minute, time.Second, config.MeterSparkline, &colors)
+	m.SetRect(0, 0, 7, 3) // внутри 5x1
+	for _, v := range []float64{0, 50, 100} {
+		m.history.push(v)
+	}
+	buf := ui.NewBuffer(m.GetRect())
+	m.Draw(buf)
+
+	want := []rune("  ▁▄█")
+	for i, r := range want {
+		if cell := buf.GetCell(image.Pt(1+i, 1)); cell.Rune != r {
+			t.Errorf("Column %d: expected %q, got %q", i, r, cell.Rune)
+		}
+	}
+	// Цвет столбца соответствует значению
+	if cell := buf.GetCell(image.Pt(5, 1)); cell.Style.Fg != ui.ColorRed {
+		t.Errorf("Expected critical color for 100%%, got %v", cell.Style.Fg)
+	}
+}
+
+func TestHistoryMeter_DrawChart(t *testing.T) {
+	colors := defaultColors
+	m := newHistoryMeter(time.Minute, time.Second, config.MeterChart, &colors)
+	m.SetRect(0, 0, 12, chartHeight)
+	for _, v := range []float64{0, 100, 0} {
+		m.history.push(v)
+	}
+	buf := ui.NewBuffer(m.GetRect())
+	m.Draw(buf)
+
+	drawn := 0
+	for x := m.Inner.Min.X; x < m.Inner.Max.X; x++ {
+		for y := m.Inner.Min.Y; y < m.Inner.Max.Y; y++ {
+			if r := buf.GetCell(image.Pt(x, y)).Rune; r >= 0x2800 && r <= 0x28FF {
+				drawn++
+			}
+		}
+	}
+	// Линия от нуля до 100% и обратно проходит через все строки графика
+	if drawn < m.Inner.Dy() {
+		t.Errorf("Expected braille line across the chart, got %d cells", drawn)
+	}
+}
+
+func TestDashboard_MeterStyles(t *testing.T) {
+	cfg := config.Default()
+	dashboard, err := newDashboardWithSource(NewMockUI(), newFakeSource(), cfg, Options{})
+	if err != nil {
+		t.Fatalf("newDashboardWithSource() вернул ошибку: %v", err)
+	}
+	dashboard.configPath = filepath.Join(t.TempDir(), "config.json")
+	for i := 0; i < 3; i++ {
+		s := system.Snapshot{CPU: []float64{10, 55, 95}, Memory: &mem.VirtualMemoryStat{UsedPercent: 25}}
+		dashboard.apply(s)
+	}
+	if got := dashboard.cpuHistory[2].history.values(); !equalFloats(got, []float64{95, 95, 95}) {
+		t.Errorf("Expected CPU history to be collected in gauge style, got %v", got)
+	}
+
+	dashboard.handleKey("m")
+	if dashboard.config.Layout.MeterStyle != config.MeterSparkline {
+		t.Fatalf("Expected sparkline style, got %q", dashboard.config.Layout.MeterStyle)
+	}
+	dashboard.render()
+	if _, ok := dashboard.ui.(*MockUI).renderedItems[0].(*historyMeter); !ok {
+		t.Errorf("Expected history meters to be rendered, got %T", dashboard.ui.(*MockUI).renderedItems[0])
+	}
+
+	dashboard.handleKey("m")
+	if got := dashboard.memHistory.GetRect().Dy(); got != chartHeight {
+		t.Errorf("Expected chart height %d, got %d", chartHeight, got)
+	}
+	saved, err := config.Load(dashboard.configPath)
+	if err != nil || saved.Layout.MeterStyle != config.MeterChart {
+		t.Errorf("Expected chart style to be saved, got %q, %v", saved.Layout.MeterStyle, err)
+	}
+
+	dashboard.handleKey("m")
+	dashboard.render()
+	if dashboard.config.Layout.MeterStyle != config.MeterGauge || dashboard.ui.(*MockUI).renderedItems[0] != dashboard.cpuCharts[0] {
+		t.Error("Expected gauges after a full cycle")
+	}
+}
+
+func equalFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"image"
+
+	ui "github.com/gizak/termui/v3"
+
+	"github.com/bonefabric/htop/internal/config"
+)
+
+// Terminal - необязательное расширение UIProvider для провайдеров, работающих
+// с настоящим терминалом: размер экрана и очистка перед перерисовкой
+type Terminal interface {
+	Dimensions() (width, height int)
+	Clear()
+}
+
+// Dimensions возвращает текущий размер терминала
+func (r *RealUI) Dimensions() (int, int) {
+	return ui.TerminalDimensions()
+}
+
+// Clear очищает экран, чтобы после уменьшения окна не оставалось старых виджетов
+func (r *RealUI) Clear() {
+	ui.Clear()
+}
+
+const (
+	gaugeHeight = 3
+	// chartHeight - высота индикатора-графика: 4 строки по 4 точки Брайля и рамка
+	chartHeight = 6
+	// defaultListHeight - высота списка процессов, если размер терминала неизвестен
+	defaultListHeight = 14
+	// minListHeight - минимальная высота списка процессов с рамкой
+	minListHeight = 3
+	// statusHeight - высота строки состояния под списком процессов
+	statusHeight = 1
+	// minGaugeWidth - минимальная ширина индикатора CPU, до которой сужаются
+	// индикаторы ради cpu_columns
+	minGaugeWidth = 10
+)
+
+// layout - геометрия виджетов для заданного размера экрана. Пустой
+// прямоугольник означает, что виджет не помещается и не рисуется.
+type layout struct {
+	cpu      []image.Rectangle // Индикаторы ядер
+	cpuTotal image.Rectangle   // Общий индикатор CPU вместо индикаторов ядер
+	mem      image.Rectangle
+	header   image.Rectangle
+	list     image.Rectangle
+	status   image.Rectangle
+}
+
+// meterHeight возвращает высоту индикаторов CPU и памяти для их вида
+func meterHeight(style string) int {
+	if style == config.MeterChart {
+		return chartHeight
+	}
+	return gaugeHeight
+}
+
+// computeLayout раскладывает виджеты на экране width x height. Индикаторы CPU
+// занимают столько столбцов, сколько помещается при ширине не меньше
+// gauge_width, но не меньше cpu_columns, и растягиваются на всю ширину.
+// Список процессов занимает всю оставшуюся высоту над строкой состояния.
+// Если индикаторы ядер не оставляют места списку минимальной высоты, вместо
+// них показывается один общий индикатор, а на совсем низком экране
+// индикаторы CPU и памяти скрываются.
+func computeLayout(width, height, cores int, cfg config.Layout) layout {
+	columns := max(width/cfg.GaugeWidth, cfg.CPUColumns)
+	columns = min(columns, cores, width/minGaugeWidth)
+	columns = max(columns, 1)
+
+	meter := meterHeight(cfg.MeterStyle)
+	cpuHeight := (cores + columns - 1) / columns * meter // округление вверх
+	// Высота, которую индикаторы могут занять, оставив место заголовкам,
+	// списку и строке состояния
+	room := height - 1 - minListHeight - statusHeight
+
+	var l layout
+	l.cpu = make([]image.Rectangle, cores)
+	switch {
+	case cpuHeight+meter <= room:
+		for i := range l.cpu {
+			column, row := i%columns, i/columns
+			l.cpu[i] = image.Rect(column*width/columns, row*meter,
+				(column+1)*width/columns, (row+1)*meter)
+		}
+	case 2*meter <= room:
+		cpuHeight = meter
+		l.cpuTotal = image.Rect(0, 0, width, meter)
+	default:
+		cpuHeight, meter = 0, 0
+	}
+
+	if meter > 0 {
+		l.mem = image.Rect(0, cpuHeight, width, cpuHeight+meter)
+	}
+	// Заголовки колонок располагаются над рамкой списка, со сдвигом на ширину рамки
+	top := cpuHeight + meter
+	l.header = image.Rect(1, top, width-1, top+1)
+
+	bottom := height - statusHeight
+	if bottom < top+1+minListHeight {
+		bottom = top + 1 + minListHeight
+	}
+	l.list = image.Rect(0, top+1, width, bottom)
+	l.status = image.Rect(0, bottom, width, bottom+statusHeight)
+	return l
+}
+
+// defaultDimensions возвращает размер экрана, если провайдер не сообщает
+// размер терминала: ширина по настройкам индикаторов и список фиксированной высоты
+func defaultDimensions(cores int, cfg config.Layout) (int, int) {
+	rows := (cores + cfg.CPUColumns - 1) / cfg.CPUColumns
+	meter := meterHeight(cfg.MeterStyle)
+	return cfg.GaugeWidth * cfg.CPUColumns, rows*meter + meter + 1 + defaultListHeight + statusHeight
+}
+
+// resize пересчитывает геометрию всех виджетов под размер экрана
+func (d *Dashboard) resize(width, height int) {
+	d.width, d.height = width, height
+	l := computeLayout(width, height, len(d.cpuCharts), d.config.Layout)
+	d.layout = l
+	for i, chart := range d.cpuCharts {
+		chart.SetRect(l.cpu[i].Min.X, l.cpu[i].Min.Y, l.cpu[i].Max.X, l.cpu[i].Max.Y)
+		d.cpuHistory[i].SetRect(l.cpu[i].Min.X, l.cpu[i].Min.Y, l.cpu[i].Max.X, l.cpu[i].Max.Y)
+	}
+	d.cpuTotalChart.SetRect(l.cpuTotal.Min.X, l.cpuTotal.Min.Y, l.cpuTotal.Max.X, l.cpuTotal.Max.Y)
+	d.cpuTotalHistory.SetRect(l.cpuTotal.Min.X, l.cpuTotal.Min.Y, l.cpuTotal.Max.X, l.cpuTotal.Max.Y)
+	d.memChart.SetRect(l.mem.Min.X, l.mem.Min.Y, l.mem.Max.X, l.mem.Max.Y)
+	d.memHistory.SetRect(l.mem.Min.X, l.mem.Min.Y, l.mem.Max.X, l.mem.Max.Y)
+	d.processHeader.SetRect(l.header.Min.X, l.header.Min.Y, l.header.Max.X, l.header.Max.Y)
+	d.processList.SetRect(l.list.Min.X, l.list.Min.Y, l.list.Max.X, l.list.Max.Y)
+	d.statusLine.SetRect(l.status.Min.X, l.status.Min.Y, l.status.Max.X, l.status.Max.Y)
+	if d.showSetup {
+		d.updateSetupMenu()
+	}
+	d.updateDetailLayout()
+}
+
+// handleResize применяет новый размер терминала и очищает экран
+func (d *Dashboard) handleResize(width, height int) {
+	if width <= 0 || height <= 0 || (width == d.width && height == d.height) {
+		return
+	}
+	d.resize(width, height)
+	if t, ok := d.ui.(Terminal); ok {
+		t.Clear()
+	}
+}
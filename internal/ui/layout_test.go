@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"image"
+	"testing"
+
+	ui "github.com/gizak/termui/v3"
+
+	"github.com/bonefabric/htop/internal/config"
+)
+
+// terminalUI - MockUI с размером терминала
+type terminalUI struct {
+	*MockUI
+	width, height int
+	cleared       int
+}
+
+func (t *terminalUI) Dimensions() (int, int) { return t.width, t.height }
+func (t *terminalUI) Clear()                 { t.cleared++ }
+
+func TestComputeLayout_CPUColumnsAdaptToWidth(t *testing.T) {
+	tests := []struct {
+		name        string
+		width       int
+		cpuColumns  int
+		wantColumns int
+	}{
+		{"Narrow", 50, 1, 1},
+		{"Two columns", 60, 1, 2},
+		{"Limited by cores", 300, 1, 8},
+		{"Narrower than gauge", 10, 1, 1},
+		{"Config is a minimum", 50, 4, 4},
+		{"Minimum limited by width", 30, 4, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Layout{CPUColumns: tt.cpuColumns, GaugeWidth: 30}
+			l := computeLayout(tt.width, 40, 8, cfg)
+			columns := 0
+			for _, r := range l.cpu {
+				if r.Min.Y == 0 {
+					columns++
+				}
+			}
+			if columns != tt.wantColumns {
+				t.Errorf("Expected %d CPU columns, got %d", tt.wantColumns, columns)
+			}
+			// Индикаторы последней колонки доходят до правого края
+			if right := l.cpu[columns-1].Max.X; right != tt.width {
+				t.Errorf("Expected gauges to fill width %d, got %d", tt.width, right)
+			}
+			wantHeight := (8 + columns - 1) / columns * gaugeHeight
+			if l.mem.Min.Y != wantHeight || l.mem.Dx() != tt.width {
+				t.Errorf("Expected memory gauge below %d rows of CPU, got %v", wantHeight, l.mem)
+			}
+		})
+	}
+}
+
+func TestComputeLayout_ListFillsHeight(t *testing.T) {
+	cfg := config.Layout{CPUColumns: 2, GaugeWidth: 40}
+	l := computeLayout(100, 50, 4, cfg)
+
+	if want := image.Rect(0, 3, 50, 6); l.cpu[1] != image.Rect(50, 0, 100, 3) || l.cpu[2] != want {
+		t.Errorf("Unexpected CPU gauges: %v", l.cpu)
+	}
+	if l.header != image.Rect(1, 9, 99, 10) {
+		t.Errorf("Unexpected header rect: %v", l.header)
+	}
+	if l.list != image.Rect(0, 10, 100, 49) {
+		t.Errorf("Expected list to fill remaining height, got %v", l.list)
+	}
+	if l.status != image.Rect(0, 49, 100, 50) {
+		t.Errorf("Expected status line in the last row, got %v", l.status)
+	}
+
+	// На совсем низком экране индикаторы скрываются, а список и строка
+	// состояния остаются в пределах экрана
+	l = computeLayout(100, 8, 4, cfg)
+	if !l.mem.Empty() || !l.cpuTotal.Empty() || l.list.Dy() < minListHeight || l.status.Max.Y != 8 {
+		t.Errorf("Expected only the list and status line, got mem %v, list %v, status %v", l.mem, l.list, l.status)
+	}
+}
+
+func TestComputeLayout_ManyCoresOnSmallScreen(t *testing.T) {
+	cfg := config.Default().Layout
+
+	// 16 ядер в 2 столбца заняли бы 24 строки: на экране 80x24 вместо
+	// индикаторов ядер показывается общий индикатор
+	l := computeLayout(80, 24, 16, cfg)
+	for i, r := range l.cpu {
+		if !r.Empty() {
+			t.Fatalf("Expected per-core meters to be collapsed, core %d at %v", i, r)
+		}
+	}
+	if l.cpuTotal != image.Rect(0, 0, 80, gaugeHeight) || l.mem.Min.Y != gaugeHeight {
+		t.Errorf("Expected one aggregate CPU row above memory, got %v, %v", l.cpuTotal, l.mem)
+	}
+	if l.list.Max.Y != 23 || l.status != image.Rect(0, 23, 80, 24) {
+		t.Errorf("Expected list and status line within the screen, got %v, %v", l.list, l.status)
+	}
+
+	// На широком экране 64 ядра занимают больше столбцов, а не 32 строки
+	l = computeLayout(400, 60, 64, cfg)
+	if !l.cpuTotal.Empty() {
+		t.Fatal("Expected per-core meters on a wide screen")
+	}
+	if rows := l.cpu[63].Max.Y / gaugeHeight; rows != 8 {
+		t.Errorf("Expected 8 columns of 8 rows, got %d rows", rows)
+	}
+	if l.list.Max.Y != 59 || l.list.Dy() < minListHeight {
+		t.Errorf("Expected list to fit the screen, got %v", l.list)
+	}
+}
+
+func TestDashboard_RendersAggregateCPU(t *testing.T) {
+	provider := &terminalUI{MockUI: NewMockUI(), width: 40, height: 11}
+	dashboard, err := NewDashboardWithSource(provider, newFakeSource(), config.Default())
+	if err != nil {
+		t.Fatalf("NewDashboardWithSource() вернул ошибку: %v", err)
+	}
+	if err := dashboard.update(); err != nil {
+		t.Fatalf("update() вернул ошибку: %v", err)
+	}
+	rendered := false
+	for _, item := range provider.renderedItems {
+		if item == dashboard.cpuTotalChart {
+			rendered = true
+		}
+		for _, chart := range dashboard.cpuCharts {
+			if item == chart {
+				t.Error("Per-core gauges must not be drawn when collapsed")
+			}
+		}
+	}
+	if !rendered {
+		t.Error("Expected the aggregate CPU gauge to be drawn")
+	}
+	// Средняя загрузка трех ядер 10%, 55% и 95%
+	if dashboard.cpuTotalChart.Percent != 53 {
+		t.Errorf("Expected average load 53%%, got %d", dashboard.cpuTotalChart.Percent)
+	}
+}
+
+func TestDashboard_Resize(t *testing.T) {
+	provider := &terminalUI{MockUI: NewMockUI(), width: 80, height: 30}
+	dashboard, err := NewDashboardWithUI(provider)
+	if err != nil {
+		t.Fatalf("NewDashboardWithUI() вернул ошибку: %v", err)
+	}
+	if rect := dashboard.processList.GetRect(); rect.Dx() != 80 || rect.Max.Y != 30-statusHeight {
+		t.Errorf("Expected list sized to terminal 80x30, got %v", rect)
+	}
+
+	go dashboard.Run()
+	provider.events <- ui.Event{Type: ui.ResizeEvent, Payload: ui.Resize{Width: 200, Height: 60}}
+	provider.events <- ui.Event{Type: ui.KeyboardEvent, ID: "q"}
+
+	if rect := dashboard.processList.GetRect(); rect.Dx() != 200 || rect.Max.Y != 60-statusHeight {
+		t.Errorf("Expected list resized to 200x60, got %v", rect)
+	}
+	if rect := dashboard.memChart.GetRect(); rect.Dx() != 200 {
+		t.Errorf("Expected memory gauge resized to width 200, got %v", rect)
+	}
+	if provider.cleared != 1 {
+		t.Errorf("Expected screen to be cleared once, got %d", provider.cleared)
+	}
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"github.com/bonefabric/htop/internal/metrics"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// RunMetrics запускает без интерфейса HTTP-сервер, отдающий метрики
+// в формате Prometheus по адресу opts.MetricsAddr, до SIGINT или SIGTERM.
+// Данные собираются теми же сборщиками и с тем же интервалом, что и в интерфейсе.
+func RunMetrics(opts Options) error {
+	cfg, _, err := loadConfig(opts)
+	if err != nil {
+		return err
+	}
+	d, err := newDashboard(headlessUI{}, cfg, opts)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return metrics.ListenAndServe(ctx, opts.MetricsAddr, d.metricsCollector(), d.refreshInterval, opts.MetricsTop)
+}
+
+// metricsCollector возвращает сборщик снимков, в которых оставлены только
+// процессы, прошедшие фильтры -u и -p
+func (d *Dashboard) metricsCollector() system.CollectFunc {
+	return func(ctx context.Context) system.Snapshot {
+		s := d.collect(ctx)
+		s.Processes = slices.DeleteFunc(s.Processes, func(p system.ProcessInfo) bool {
+			return !d.matchesOptions(p)
+		})
+		return s
+	}
+}
@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bonefabric/htop/internal/config"
+)
+
+func TestDashboard_MetricsCollector(t *testing.T) {
+	dashboard, err := newDashboardWithSource(headlessUI{}, newFakeSource(), config.Default(), Options{User: "alice"})
+	if err != nil {
+		t.Fatalf("newDashboardWithSource() вернул ошибку: %v", err)
+	}
+	s := dashboard.metricsCollector()(context.Background())
+	if !equalPIDs(pids(s.Processes), []int32{200}) {
+		t.Errorf("Expected only processes of alice, got %v", pids(s.Processes))
+	}
+	if len(s.CPU) != 3 || s.Memory == nil {
+		t.Errorf("Expected system metrics to be collected, got %+v", s)
+	}
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// Options содержит параметры запуска, которые переопределяют настройки из файла
+// только на время работы приложения
+type Options struct {
+	ConfigPath   string        // путь к файлу настроек, пустой - путь по умолчанию
+	Delay        time.Duration // интервал обновления, 0 - из настроек
+	User         string        // показывать только процессы пользователя
+	PIDs         []int32       // показывать только указанные процессы
+	SortColumn   string        // колонка сортировки, пустая - из настроек
+	Tree         bool          // включить отображение дерева
+	Monochrome   bool          // не использовать цвета
+	Batch        bool          // пакетный режим: печатать снимки в stdout без интерфейса
+	Iterations   int           // количество обновлений в пакетном режиме, 0 - без ограничения
+	Export       string        // формат выгрузки в пакетном режиме (json, ndjson, csv), пустой - текст
+	ExportFields string        // поля процессов для выгрузки через запятую, пустая строка - все
+	ProcRoot     string        // каталог procfs вместо данных текущей машины (только Linux)
+	RecordPath   string        // файл записи снимков в пакетном режиме
+	ReplayPath   string        // файл записи для воспроизведения вместо живых данных
+	MetricsAddr  string        // адрес HTTP-сервера метрик Prometheus вместо интерфейса
+	MetricsTop   int           // количество процессов в метриках по CPU и по памяти
+}
+
+// matchesOptions проверяет, проходит ли процесс фильтры по пользователю и PID
+// из параметров запуска
+func (d *Dashboard) matchesOptions(p system.ProcessInfo) bool {
+	if d.options.User != "" && p.User != d.options.User {
+		return false
+	}
+	if len(d.options.PIDs) > 0 {
+		for _, pid := range d.options.PIDs {
+			if pid == p.PID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// applyMonochrome убирает цвета из всех виджетов: индикаторы рисуются цветом
+// терминала по умолчанию, выделение строк - инверсией
+func (d *Dashboard) applyMonochrome() {
+	plain := ui.NewStyle(ui.ColorClear)
+	selected := ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierReverse)
+
+	for i := range d.colors.colors {
+		d.colors.colors[i] = ui.ColorWhite
+	}
+	for _, chart := range append([]*widgets.Gauge{d.cpuTotalChart}, d.cpuCharts...) {
+		chart.BarColor = ui.ColorWhite
+		chart.BorderStyle = plain
+		chart.TitleStyle = plain
+	}
+	d.memChart.BarColor = ui.ColorWhite
+	d.memChart.BorderStyle = plain
+	d.memChart.TitleStyle = plain
+	for _, meter := range append([]*historyMeter{d.memHistory, d.cpuTotalHistory}, d.cpuHistory...) {
+		meter.BorderStyle = plain
+		meter.TitleStyle = plain
+	}
+	d.processHeader.TextStyle = selected
+
+	for _, list := range []*ui.Block{&d.processList.Block, &d.signalMenu.Block, &d.ioMenu.Block, &d.setupMenu.Block} {
+		list.BorderStyle = plain
+		list.TitleStyle = plain
+	}
+	d.processList.TextStyle = plain
+	d.processList.SelectedRowStyle = selected
+	d.signalMenu.TextStyle = plain
+	d.signalMenu.SelectedRowStyle = selected
+	d.ioMenu.TextStyle = plain
+	d.ioMenu.SelectedRowStyle = selected
+	d.setupMenu.TextStyle = plain
+	d.setupMenu.SelectedRowStyle = selected
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+func TestNewDashboard_Options(t *testing.T) {
+	opts := Options{
+		Delay:      3 * time.Second,
+		SortColumn: "pid",
+		Tree:       true,
+	}
+	dashboard, err := newDashboard(NewMockUI(), config.Default(), opts)
+	if err != nil {
+		t.Fatalf("newDashboard() вернул ошибку: %v", err)
+	}
+	if dashboard.refreshInterval != 3*time.Second {
+		t.Errorf("Expected delay from options, got %v", dashboard.refreshInterval)
+	}
+	if dashboard.sortKey != SortByPID || dashboard.sortDesc {
+		t.Errorf("Expected ascending PID sort, got %v desc=%v", dashboard.sortKey, dashboard.sortDesc)
+	}
+	if !dashboard.treeView {
+		t.Error("Expected tree view from options")
+	}
+	// Параметры запуска не меняют сохраняемые настройки
+	if dashboard.config.Sort.Column != "CPU" || dashboard.config.TreeView {
+		t.Errorf("Options must not leak into config, got %+v", dashboard.config)
+	}
+}
+
+func TestNewDashboard_OptionsNotSaved(t *testing.T) {
+	dashboard, err := newDashboard(NewMockUI(), config.Default(), Options{Tree: true, SortColumn: "pid"})
+	if err != nil {
+		t.Fatalf("newDashboard() вернул ошибку: %v", err)
+	}
+	dashboard.configPath = filepath.Join(t.TempDir(), "config.json")
+
+	// Сохранение по другому поводу не записывает -t и -s в файл
+	dashboard.handleKey("<F2>")
+	dashboard.handleKey("<Space>")
+	saved, err := config.Load(dashboard.configPath)
+	if err != nil {
+		t.Fatalf("Failed to load saved config: %v", err)
+	}
+	if saved.TreeView || saved.Sort != config.Default().Sort {
+		t.Errorf("Expected tree view and sort from the file, got tree_view=%v sort=%+v", saved.TreeView, saved.Sort)
+	}
+	if len(saved.Columns) != len(defaultColumnIDs)-1 {
+		t.Errorf("Expected the column change to be saved, got %v", saved.Columns)
+	}
+}
+
+func TestNewDashboard_UnknownSortColumn(t *testing.T) {
+	_, err := newDashboard(NewMockUI(), config.Default(), Options{SortColumn: "FOO"})
+	if err == nil || !strings.Contains(err.Error(), `unknown sort column "FOO"`) {
+		t.Errorf("Expected unknown sort column error, got %v", err)
+	}
+}
+
+func TestDashboard_UserAndPIDOptions(t *testing.T) {
+	processes := []system.ProcessInfo{
+		{PID: 1, Name: "init", User: "root"},
+		{PID: 2, Name: "bash", User: "alice"},
+		{PID: 3, Name: "vim", User: "alice"},
+	}
+
+	dashboard, err := newDashboard(NewMockUI(), config.Default(), Options{User: "alice"})
+	if err != nil {
+		t.Fatalf("newDashboard() вернул ошибку: %v", err)
+	}
+	dashboard.allProcesses = processes
+	dashboard.refreshProcessList()
+	if len(dashboard.processes) != 2 {
+		t.Errorf("Expected only alice's processes, got %v", pids(dashboard.processes))
+	}
+	if !strings.Contains(dashboard.processList.Title, "[user: alice]") {
+		t.Errorf("Expected user filter in title, got %q", dashboard.processList.Title)
+	}
+
+	dashboard, err = newDashboard(NewMockUI(), config.Default(), Options{PIDs: []int32{1, 3}})
+	if err != nil {
+		t.Fatalf("newDashboard() вернул ошибку: %v", err)
+	}
+	dashboard.allProcesses = processes
+	dashboard.refreshProcessList()
+	if got := pids(dashboard.processes); len(got) != 2 || got[0] == 2 || got[1] == 2 {
+		t.Errorf("Expected PIDs 1 and 3, got %v", got)
+	}
+	if !strings.Contains(dashboard.processList.Title, "[pid: 1,3]") {
+		t.Errorf("Expected PID filter in title, got %q", dashboard.processList.Title)
+	}
+}
+
+func TestDashboard_Monochrome(t *testing.T) {
+	dashboard, err := newDashboard(NewMockUI(), config.Default(), Options{Monochrome: true})
+	if err != nil {
+		t.Fatalf("newDashboard() вернул ошибку: %v", err)
+	}
+	for _, percent := range []int{60, 75, 95} {
+		if dashboard.colors.colorFor(percent) != dashboard.colors.colorFor(10) {
+			t.Errorf("Expected the same color for %d%% as for low load in monochrome mode", percent)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// ioMenuWidth - ширина меню приоритетов ввода-вывода
+const ioMenuWidth = 36
+
+// errNoPriority - источник данных не умеет менять приоритеты процессов
+var errNoPriority = errors.New("changing priority is not supported for this data source")
+
+// ioPriorityChoices - приоритеты в меню ввода-вывода: без класса, realtime
+// и best-effort по уровням, idle
+var ioPriorityChoices = func() []system.IOPriority {
+	choices := []system.IOPriority{{Class: system.IOClassNone}}
+	for _, class := range []system.IOClass{system.IOClassRealtime, system.IOClassBestEffort} {
+		for level := int32(0); level <= system.MaxIOLevel; level++ {
+			choices = append(choices, system.IOPriority{Class: class, Level: level})
+		}
+	}
+	return append(choices, system.IOPriority{Class: system.IOClassIdle})
+}()
+
+// newIOMenu создает меню выбора приоритета ввода-вывода
+func newIOMenu() *widgets.List {
+	menu := widgets.NewList()
+	menu.Title = "I/O Priority (Enter to set, ← to cancel)"
+	menu.TextStyle = ui.NewStyle(ui.ColorWhite)
+	menu.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorYellow)
+	menu.WrapText = false
+	menu.Rows = make([]string, len(ioPriorityChoices))
+	for i, prio := range ioPriorityChoices {
+		menu.Rows[i] = fmt.Sprintf("%-2s - %s", prio, describeIOPriority(prio))
+	}
+	return menu
+}
+
+// describeIOPriority возвращает пояснение к приоритету для меню
+func describeIOPriority(prio system.IOPriority) string {
+	switch prio.Class {
+	case system.IOClassRealtime:
+		return fmt.Sprintf("Realtime, level %d", prio.Level)
+	case system.IOClassBestEffort:
+		return fmt.Sprintf("Best effort, level %d", prio.Level)
+	case system.IOClassIdle:
+		return "Idle"
+	}
+	return "Follow nice (default)"
+}
+
+// selectedProcess возвращает процесс под курсором
+func (d *Dashboard) selectedProcess() (system.ProcessInfo, bool) {
+	if d.selectedRow >= len(d.processes) {
+		return system.ProcessInfo{}, false
+	}
+	return d.processes[d.selectedRow], true
+}
+
+// renice изменяет nice выбранного или отмеченных процессов на delta
+// в пределах допустимого диапазона
+func (d *Dashboard) renice(delta int32) {
+	source, supported := d.source.(system.PrioritySource)
+	d.runAction("renice", d.targets(), func(p system.ProcessInfo) error {
+		if !supported {
+			return errNoPriority
+		}
+		nice := min(max(p.Nice+delta, system.MinNice), system.MaxNice)
+		if nice == p.Nice {
+			return nil
+		}
+		if err := source.SetNice(p.PID, nice); err != nil {
+			return err
+		}
+		// Новое значение видно сразу, не дожидаясь следующего замера:
+		// повторное нажатие продолжает от него
+		d.updateProcess(p.PID, func(p *system.ProcessInfo) { p.Nice = nice })
+		return nil
+	})
+	d.refreshProcessList()
+}
+
+// openIOMenu показывает меню приоритетов ввода-вывода с текущим приоритетом
+// выбранного процесса под курсором
+func (d *Dashboard) openIOMenu() {
+	p, ok := d.selectedProcess()
+	if !ok {
+		return
+	}
+	d.showIOMenu = true
+	d.menuTargets = d.targets()
+	d.ioMenu.SelectedRow = 0
+	for i, prio := range ioPriorityChoices {
+		if prio == p.IOPriority {
+			d.ioMenu.SelectedRow = i
+		}
+	}
+}
+
+// handleIOMenuKey обрабатывает клавиши в меню приоритетов ввода-вывода
+func (d *Dashboard) handleIOMenuKey(id string) {
+	switch id {
+	case "<Left>", "<Escape>":
+		d.showIOMenu = false
+	case "<Up>":
+		d.ioMenu.ScrollUp()
+	case "<Down>":
+		d.ioMenu.ScrollDown()
+	case "<Enter>":
+		d.showIOMenu = false
+		d.setIOPriority(ioPriorityChoices[d.ioMenu.SelectedRow], d.menuTargets)
+	}
+}
+
+// setIOPriority задает приоритет ввода-вывода процессов targets
+func (d *Dashboard) setIOPriority(prio system.IOPriority, targets actionTargets) {
+	source, supported := d.source.(system.PrioritySource)
+	d.runAction("set I/O priority of", targets, func(p system.ProcessInfo) error {
+		if !supported {
+			return errNoPriority
+		}
+		if err := source.SetIOPriority(p.PID, prio); err != nil {
+			return err
+		}
+		d.updateProcess(p.PID, func(p *system.ProcessInfo) { p.IOPriority = prio })
+		return nil
+	})
+	d.refreshProcessList()
+}
+
+// updateProcess изменяет сохраненные данные процесса. Список перестраивается
+// вызывающим после изменения всех процессов.
+func (d *Dashboard) updateProcess(pid int32, change func(p *system.ProcessInfo)) {
+	for i := range d.allProcesses {
+		if d.allProcesses[i].PID == pid {
+			change(&d.allProcesses[i])
+		}
+	}
+}
+
+// updateIOMenuPosition располагает меню приоритетов ввода-вывода у курсора
+func (d *Dashboard) updateIOMenuPosition() {
+	rect := d.menuRect(ioMenuWidth, len(ioPriorityChoices))
+	d.ioMenu.SetRect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y)
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// newSourceDashboard создает Dashboard с фиктивным источником после первого обновления
+func newSourceDashboard(t *testing.T, source *fakeSource) *Dashboard {
+	t.Helper()
+	dashboard, err := NewDashboardWithSource(NewMockUI(), source, config.Default())
+	if err != nil {
+		t.Fatalf("NewDashboardWithSource() вернул ошибку: %v", err)
+	}
+	if err := dashboard.update(); err != nil {
+		t.Fatalf("update() вернул ошибку: %v", err)
+	}
+	return dashboard
+}
+
+// newPriorityDashboard создает Dashboard с фиктивным источником и выбранным PID 200
+func newPriorityDashboard(t *testing.T, source *fakeSource) *Dashboard {
+	t.Helper()
+	dashboard := newSourceDashboard(t, source)
+	if dashboard.processes[0].PID != 200 {
+		t.Fatalf("Expected PID 200 selected, got %v", pids(dashboard.processes))
+	}
+	return dashboard
+}
+
+func TestDashboard_Renice(t *testing.T) {
+	source := newFakeSource()
+	source.processes[1].Nice = 18
+	dashboard := newPriorityDashboard(t, source)
+
+	// Повторное нажатие продолжает от нового значения, не дожидаясь замера
+	dashboard.handleKey("<F8>")
+	if source.nices[200] != 19 || dashboard.processes[0].Nice != 19 {
+		t.Fatalf("Expected nice 19 after F8, got %d (shown %d)", source.nices[200], dashboard.processes[0].Nice)
+	}
+	delete(source.nices, 200)
+	dashboard.handleKey("<F8>")
+	if _, ok := source.nices[200]; ok {
+		t.Error("Expected no renice above the maximum nice")
+	}
+
+	dashboard.handleKey("<F7>")
+	dashboard.handleKey("]")
+	if source.nices[200] != 17 {
+		t.Errorf("Expected nice 17 after F7 and ], got %d", source.nices[200])
+	}
+}
+
+func TestDashboard_ReniceError(t *testing.T) {
+	source := newFakeSource()
+	source.priorityErr = errors.New("permission denied")
+	dashboard := newPriorityDashboard(t, source)
+
+	dashboard.handleKey("<F7>")
+	if dashboard.processes[0].Nice != 0 {
+		t.Errorf("Expected nice to stay unchanged on error, got %d", dashboard.processes[0].Nice)
+	}
+}
+
+func TestDashboard_IOPriorityMenu(t *testing.T) {
+	source := newFakeSource()
+	source.processes[1].IOPriority = system.IOPriority{Class: system.IOClassBestEffort, Level: 4}
+	dashboard := newPriorityDashboard(t, source)
+
+	dashboard.handleKey("i")
+	if !dashboard.showIOMenu {
+		t.Fatal("Expected i to open the I/O priority menu")
+	}
+	// Курсор стоит на текущем приоритете процесса
+	if row := dashboard.ioMenu.Rows[dashboard.ioMenu.SelectedRow]; !strings.HasPrefix(row, "B4") {
+		t.Errorf("Expected the current priority selected, got %q", row)
+	}
+
+	dashboard.handleKey("<Down>")
+	dashboard.handleKey("<Enter>")
+	want := system.IOPriority{Class: system.IOClassBestEffort, Level: 5}
+	if dashboard.showIOMenu || source.ioPriorities[200] != want {
+		t.Errorf("Expected B5 for PID 200 and a closed menu, got %v", source.ioPriorities)
+	}
+	if dashboard.processes[0].IOPriority != want {
+		t.Errorf("Expected the new priority shown at once, got %v", dashboard.processes[0].IOPriority)
+	}
+
+	dashboard.handleKey("i")
+	dashboard.handleKey("<Escape>")
+	if dashboard.showIOMenu || len(source.ioPriorities) != 1 {
+		t.Error("Expected Escape to close the menu without changes")
+	}
+}
+
+func TestIOPriorityColumn(t *testing.T) {
+	c, ok := findColumn("IO_PRIORITY")
+	if !ok {
+		t.Fatal("Expected IO_PRIORITY column")
+	}
+	// Без заданного класса показывается приоритет, следующий из nice
+	if got := c.format(system.ProcessInfo{Nice: 19}); got != "B7" {
+		t.Errorf("Expected B7 for nice 19, got %q", got)
+	}
+	if got := c.format(system.ProcessInfo{IOPriority: system.IOPriority{Class: system.IOClassIdle}}); got != "id" {
+		t.Errorf("Expected id for idle class, got %q", got)
+	}
+}
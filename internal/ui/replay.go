@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// startReplay показывает первый кадр записи и возвращает канал таймера,
+// по которому показываются следующие кадры
+func (d *Dashboard) startReplay() <-chan time.Time {
+	d.replayTimer = time.NewTimer(time.Hour)
+	d.showFrame()
+	return d.replayTimer.C
+}
+
+// nextFrame переходит к следующему кадру по таймеру
+func (d *Dashboard) nextFrame() {
+	d.player.Advance()
+	d.showFrame()
+}
+
+// showFrame отображает текущий кадр записи тем же кодом, что и живые данные,
+// и планирует показ следующего
+func (d *Dashboard) showFrame() {
+	// Сохраненная в кадре ProcessErr показывается в строке состояния, как вживую
+	d.apply(d.player.Current().Snapshot())
+	d.scheduleFrame()
+}
+
+// scheduleFrame обновляет состояние воспроизведения в заголовке и планирует
+// показ следующего кадра, не показывая текущий заново
+func (d *Dashboard) scheduleFrame() {
+	d.updateProcessListTitle()
+
+	if !d.replayTimer.Stop() {
+		select {
+		case <-d.replayTimer.C:
+		default:
+		}
+	}
+	if delay, ok := d.player.Delay(); ok {
+		d.replayTimer.Reset(delay)
+	}
+}
+
+// handleReplayKey обрабатывает клавиши управления воспроизведением.
+// Возвращает false, если клавиша к воспроизведению не относится.
+func (d *Dashboard) handleReplayKey(id string) bool {
+	pos, _ := d.player.Position()
+	switch id {
+	case "p":
+		d.player.TogglePause()
+	case ".":
+		d.player.Step(1)
+	case ",":
+		d.player.Step(-1)
+	case "]":
+		d.player.Seek(time.Minute)
+	case "[":
+		d.player.Seek(-time.Minute)
+	case "<Home>":
+		d.player.SeekStart()
+	case "<End>":
+		d.player.SeekEnd()
+	case "}":
+		d.player.Faster()
+	case "{":
+		d.player.Slower()
+	default:
+		return false
+	}
+	// Пауза и смена скорости кадр не меняют: повторный показ добавил бы
+	// в историю индикаторов те же значения еще раз
+	next, _ := d.player.Position()
+	if next == pos {
+		d.scheduleFrame()
+		return true
+	}
+	// После перехода по записи история индикаторов уже не непрерывна
+	if next != pos+1 {
+		d.resetHistory()
+	}
+	d.showFrame()
+	return true
+}
+
+// replayIndicator возвращает состояние воспроизведения для заголовка списка
+func (d *Dashboard) replayIndicator() string {
+	state := "▶"
+	if d.player.Paused() {
+		state = "❚❚"
+	}
+	pos, total := d.player.Position()
+	return fmt.Sprintf(" [replay %s %s x%g %d/%d]",
+		d.player.Current().Time.Format("2006-01-02 15:04:05"), state, d.player.Speed(), pos+1, total)
+}
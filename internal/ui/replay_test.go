@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/record"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// replayFrames возвращает n кадров с интервалом в секунду; в кадре i
+// работает процесс с PID 100+i
+func replayFrames(n int) []record.Frame {
+	start := time.Date(2024, 5, 1, 3, 0, 0, 0, time.Local)
+	frames := make([]record.Frame, n)
+	for i := range frames {
+		frames[i] = record.Frame{
+			Time:      start.Add(time.Duration(i) * time.Second),
+			CPU:       []float64{float64(i), 50},
+			Memory:    mem.VirtualMemoryStat{Total: 100, Used: uint64(i), UsedPercent: float64(i)},
+			Processes: []system.ProcessInfo{{PID: 1, Name: "init"}, {PID: int32(100 + i), Name: "job"}},
+		}
+	}
+	return frames
+}
+
+func newReplayDashboard(t *testing.T, frames []record.Frame) *Dashboard {
+	t.Helper()
+	dashboard, err := NewDashboardWithSource(NewMockUI(), record.NewPlayer(frames), config.Default())
+	if err != nil {
+		t.Fatalf("NewDashboardWithSource() вернул ошибку: %v", err)
+	}
+	if dashboard.player == nil {
+		t.Fatal("Expected replay mode for a player source")
+	}
+	dashboard.startReplay()
+	t.Cleanup(func() { dashboard.replayTimer.Stop() })
+	return dashboard
+}
+
+func TestDashboard_ReplayControls(t *testing.T) {
+	dashboard := newReplayDashboard(t, replayFrames(150))
+	if !containsPID(dashboard.processes, 100) {
+		t.Fatalf("Expected first frame to be shown, got %v", pids(dashboard.processes))
+	}
+	if title := dashboard.processList.Title; !strings.Contains(title, "[replay 2024-05-01 03:00:00 ▶ x1 1/150]") {
+		t.Errorf("Expected replay indicator in title, got %q", title)
+	}
+
+	tests := []struct {
+		key     string
+		wantPID int32
+	}{
+		{".", 101},
+		{".", 102},
+		{",", 101},
+		{"]", 161},
+		{"[", 101},
+		{"<End>", 249},
+		{"<Home>", 100},
+	}
+	for _, tt := range tests {
+		dashboard.handleKey(tt.key)
+		if !containsPID(dashboard.processes, tt.wantPID) {
+			t.Errorf("After %q expected PID %d, got %v", tt.key, tt.wantPID, pids(dashboard.processes))
+		}
+	}
+	if !dashboard.player.Paused() {
+		t.Error("Expected stepping to pause playback")
+	}
+
+	dashboard.handleKey("p")
+	dashboard.handleKey("}")
+	if title := dashboard.processList.Title; !strings.Contains(title, "▶ x2 1/150") {
+		t.Errorf("Expected resumed playback at x2, got %q", title)
+	}
+	if dashboard.cpuCharts[0].Percent != 0 || dashboard.memChart.Percent != 0 {
+		t.Errorf("Expected meters of the first frame, got cpu=%d mem=%d",
+			dashboard.cpuCharts[0].Percent, dashboard.memChart.Percent)
+	}
+}
+
+func TestDashboard_ReplayControlsKeepHistory(t *testing.T) {
+	dashboard := newReplayDashboard(t, replayFrames(5))
+	dashboard.handleKey("<Enter>")
+	if dashboard.detail == nil {
+		t.Fatal("Expected Enter to open the detail pane")
+	}
+	// Панель перехватывает клавиши, поэтому управление вызывается напрямую
+	dashboard.handleReplayKey(".")
+	lengths := func() []int {
+		return []int{
+			len(dashboard.cpuHistory[0].history.values()),
+			len(dashboard.cpuTotalHistory.history.values()),
+			len(dashboard.memHistory.history.values()),
+			len(dashboard.detail.cpu.history.values()),
+		}
+	}
+	want := lengths()
+	if want[0] != 2 {
+		t.Fatalf("Expected two samples after a step, got %v", want)
+	}
+
+	// Пауза и скорость кадр не меняют и не добавляют замеров
+	for _, key := range []string{"p", "}", "{", "p"} {
+		dashboard.handleReplayKey(key)
+		if got := lengths(); !slices.Equal(got, want) {
+			t.Errorf("After %q expected history lengths %v, got %v", key, want, got)
+		}
+	}
+	if title := dashboard.processList.Title; !strings.Contains(title, "❚❚ x1 2/5") {
+		t.Errorf("Expected the title to follow pause and speed, got %q", title)
+	}
+
+	// Шаг за пределы записи тоже оставляет кадр прежним
+	dashboard.handleReplayKey("<End>")
+	want = lengths()
+	dashboard.handleReplayKey(".")
+	if got := lengths(); !slices.Equal(got, want) {
+		t.Errorf("Expected no samples when stepping past the end, got %v, want %v", got, want)
+	}
+}
+
+func TestDashboard_ReplayPlaysFrames(t *testing.T) {
+	frames := replayFrames(3)
+	for i := range frames {
+		frames[i].Time = frames[0].Time.Add(time.Duration(i) * 10 * time.Millisecond)
+	}
+	dashboard := newReplayDashboard(t, frames)
+
+	deadline := time.After(time.Second)
+	for !dashboard.player.Paused() {
+		select {
+		case <-dashboard.replayTimer.C:
+			dashboard.nextFrame()
+		case <-deadline:
+			t.Fatal("Replay did not reach the last frame")
+		}
+	}
+	if !containsPID(dashboard.processes, 102) {
+		t.Errorf("Expected last frame to be shown, got %v", pids(dashboard.processes))
+	}
+}
+
+func TestDashboard_RecordFrames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.rec")
+	writer, err := record.Create(path)
+	if err != nil {
+		t.Fatalf("record.Create() вернул ошибку: %v", err)
+	}
+	// Фильтр не влияет на запись: сохраняются все процессы
+	dashboard, err := newDashboardWithSource(headlessUI{}, newFakeSource(), config.Default(), Options{User: "alice", Delay: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("newDashboardWithSource() вернул ошибку: %v", err)
+	}
+	if err := dashboard.runBatch(2, recordFrames(writer)); err != nil {
+		t.Fatalf("runBatch() вернул ошибку: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() вернул ошибку: %v", err)
+	}
+
+	frames, err := record.ReadFile(path)
+	if err != nil {
+		t.Fatalf("record.ReadFile() вернул ошибку: %v", err)
+	}
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 recorded frames, got %d", len(frames))
+	}
+	if got := pids(frames[1].Processes); !equalPIDs(got, []int32{1, 200}) && !equalPIDs(got, []int32{200, 1}) {
+		t.Errorf("Expected all processes to be recorded, got %v", got)
+	}
+	if len(frames[1].CPU) != 3 || frames[1].Memory.UsedPercent != 25 {
+		t.Errorf("Expected meters to be recorded, got %+v", frames[1])
+	}
+	if frames[1].ProcessErr != "" {
+		t.Errorf("Unexpected process error %q", frames[1].ProcessErr)
+	}
+}
+
+func TestDashboard_RecordProcessError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.rec")
+	writer, err := record.Create(path)
+	if err != nil {
+		t.Fatalf("record.Create() вернул ошибку: %v", err)
+	}
+	source := newFakeSource()
+	source.processErr = errors.New("permission denied")
+	dashboard, err := newDashboardWithSource(headlessUI{}, source, config.Default(), Options{})
+	if err != nil {
+		t.Fatalf("newDashboardWithSource() вернул ошибку: %v", err)
+	}
+	if err := dashboard.runBatch(1, recordFrames(writer)); err != nil {
+		t.Fatalf("runBatch() вернул ошибку: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() вернул ошибку: %v", err)
+	}
+
+	frames, err := record.ReadFile(path)
+	if err != nil {
+		t.Fatalf("record.ReadFile() вернул ошибку: %v", err)
+	}
+	// Ошибка сохраняется вместе с остальными данными кадра
+	if len(frames) != 1 || frames[0].ProcessErr != "permission denied" || len(frames[0].CPU) != 3 {
+		t.Fatalf("Expected the process error to be recorded, got %+v", frames)
+	}
+	// и при воспроизведении показывается в строке состояния
+	replay := newReplayDashboard(t, frames)
+	if text := replay.statusLine.Text; !strings.Contains(text, "Failed to get process list: permission denied") {
+		t.Errorf("Expected the recorded error in the status line, got %q", text)
+	}
+}
+
+func containsPID(processes []system.ProcessInfo, pid int32) bool {
+	for _, p := range processes {
+		if p.PID == pid {
+			return true
+		}
+	}
+	return false
+}
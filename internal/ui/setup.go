@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+)
+
+// setupEntry - строка экрана настройки колонок
+type setupEntry struct {
+	column column
+	active bool
+}
+
+// setupEntries возвращает строки экрана настройки: сначала активные колонки
+// в порядке отображения, затем остальные колонки реестра
+func (d *Dashboard) setupEntries() []setupEntry {
+	entries := make([]setupEntry, 0, len(columnRegistry))
+	active := make(map[string]bool, len(d.columns))
+	for _, c := range d.columns {
+		entries = append(entries, setupEntry{column: c, active: true})
+		active[c.ID] = true
+	}
+	for _, c := range columnRegistry {
+		if !active[c.ID] {
+			entries = append(entries, setupEntry{column: c})
+		}
+	}
+	return entries
+}
+
+// openSetup показывает экран настройки колонок
+func (d *Dashboard) openSetup() {
+	d.showSetup = true
+	d.setupRow = 0
+	d.updateSetupMenu()
+}
+
+// handleSetupKey обрабатывает нажатия клавиш на экране настройки колонок
+func (d *Dashboard) handleSetupKey(id string) {
+	entries := d.setupEntries()
+	switch id {
+	case "<Escape>", "<F2>", "<F10>", "q":
+		d.showSetup = false
+		return
+	case "<Up>":
+		if d.setupRow > 0 {
+			d.setupRow--
+		}
+	case "<Down>":
+		if d.setupRow < len(entries)-1 {
+			d.setupRow++
+		}
+	case "<Space>", "<Enter>":
+		d.toggleColumn(d.setupRow)
+	case "<F7>", "[":
+		d.moveColumn(d.setupRow, -1)
+	case "<F8>", "]":
+		d.moveColumn(d.setupRow, 1)
+	}
+	d.updateSetupMenu()
+}
+
+// toggleColumn добавляет колонку в конец набора или убирает ее.
+// Последнюю активную колонку убрать нельзя.
+func (d *Dashboard) toggleColumn(row int) {
+	entries := d.setupEntries()
+	if row < 0 || row >= len(entries) {
+		return
+	}
+	entry := entries[row]
+	if entry.active {
+		if len(d.columns) == 1 {
+			return
+		}
+		d.columns = append(d.columns[:row:row], d.columns[row+1:]...)
+		// Курсор остается на той же колонке, которая переместилась в неактивные
+		for i, e := range d.setupEntries() {
+			if e.column.ID == entry.column.ID {
+				d.setupRow = i
+			}
+		}
+	} else {
+		d.columns = append(d.columns, entry.column)
+		d.setupRow = len(d.columns) - 1
+	}
+	d.applyColumns()
+}
+
+// moveColumn перемещает активную колонку на delta позиций
+func (d *Dashboard) moveColumn(row, delta int) {
+	target := row + delta
+	if row < 0 || row >= len(d.columns) || target < 0 || target >= len(d.columns) {
+		return
+	}
+	d.columns[row], d.columns[target] = d.columns[target], d.columns[row]
+	d.setupRow = target
+	d.applyColumns()
+}
+
+// applyColumns перестраивает заголовок и строки списка после изменения колонок
+func (d *Dashboard) applyColumns() {
+	d.processHeader.Text = formatHeader(d.columns)
+	d.refreshProcessList()
+	d.config.Columns = columnIDs(d.columns)
+	d.saveConfig()
+}
+
+// updateSetupMenu обновляет строки и позицию экрана настройки колонок
+func (d *Dashboard) updateSetupMenu() {
+	entries := d.setupEntries()
+	rows := make([]string, len(entries))
+	for i, e := range entries {
+		mark := "[ ]"
+		if e.active {
+			mark = "[x]"
+		}
+		rows[i] = fmt.Sprintf("%s %-9s %s", mark, e.column.ID, e.column.Description)
+	}
+	d.setupMenu.Rows = rows
+	d.setupMenu.SelectedRow = d.setupRow
+
+	rect := d.processList.GetRect()
+	width := 60
+	if width > rect.Dx() {
+		width = rect.Dx()
+	}
+	height := len(rows) + 2 // +2 для рамки
+	if height > rect.Dy() {
+		height = rect.Dy() // на маленьком экране список прокручивается внутри рамки
+	}
+	x1 := rect.Min.X + (rect.Dx()-width)/2
+	d.setupMenu.SetRect(x1, rect.Min.Y, x1+width, rect.Min.Y+height)
+}
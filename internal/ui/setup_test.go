@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColumnRegistryIsConsistent(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, c := range columnRegistry {
+		if c.ID == "" || c.Title == "" || c.Description == "" || c.format == nil {
+			t.Errorf("Column %q is incomplete", c.ID)
+		}
+		if seen[c.ID] {
+			t.Errorf("Duplicate column ID %q", c.ID)
+		}
+		seen[c.ID] = true
+	}
+	for _, id := range defaultColumnIDs {
+		if !seen[id] {
+			t.Errorf("Default column %q is not registered", id)
+		}
+	}
+}
+
+func TestColumnsByID(t *testing.T) {
+	columns := columnsByID([]string{"CPU", "UNKNOWN", "PID", "CPU"})
+	if got := columnIDs(columns); strings.Join(got, ",") != "CPU,PID" {
+		t.Errorf("columnsByID() = %v, want [CPU PID]", got)
+	}
+}
+
+func TestFormatRow_FlexibleColumnInTheMiddle(t *testing.T) {
+	columns := columnsByID([]string{"COMMAND", "PID"})
+	header := formatHeader(columns)
+	row := formatRow(columns, testProcesses()[0], "")
+
+	// PID выравнивается по правому краю: сравниваем концы значений
+	if strings.Index(header, "PID")+len("PID") != strings.Index(row, "10")+len("10") {
+		t.Errorf("Columns after command are misaligned:\n%s\n%s", header, row)
+	}
+}
+
+func TestDashboard_Setup(t *testing.T) {
+	dashboard, err := NewDashboardWithUI(NewMockUI())
+	if err != nil {
+		t.Fatalf("Failed to create dashboard: %v", err)
+	}
+	dashboard.allProcesses = testProcesses()
+	dashboard.refreshProcessList()
+
+	dashboard.handleKey("<F2>")
+	if !dashboard.showSetup {
+		t.Fatal("Expected F2 to open column setup")
+	}
+	if len(dashboard.setupMenu.Rows) != len(columnRegistry) {
+		t.Errorf("Expected %d rows in setup, got %d", len(columnRegistry), len(dashboard.setupMenu.Rows))
+	}
+
+	// Убираем первую колонку (PID)
+	dashboard.handleKey("<Space>")
+	if dashboard.columns[0].ID == "PID" {
+		t.Error("Expected PID column to be removed")
+	}
+	if strings.HasPrefix(strings.TrimSpace(dashboard.processHeader.Text), "PID") {
+		t.Errorf("Expected header without PID, got %q", dashboard.processHeader.Text)
+	}
+	// Курсор следует за убранной колонкой в список неактивных
+	if !strings.HasPrefix(dashboard.setupMenu.Rows[dashboard.setupRow], "[ ] PID") {
+		t.Errorf("Expected cursor on inactive PID, got %q", dashboard.setupMenu.Rows[dashboard.setupRow])
+	}
+
+	// Возвращаем PID: он добавляется в конец и поднимается на одну позицию
+	dashboard.handleKey("<Enter>")
+	last := len(dashboard.columns) - 1
+	if dashboard.columns[last].ID != "PID" {
+		t.Fatalf("Expected PID to be appended, got %v", columnIDs(dashboard.columns))
+	}
+	dashboard.handleKey("<F7>")
+	if dashboard.columns[last-1].ID != "PID" || dashboard.columns[last].ID != "COMMAND" {
+		t.Errorf("Expected PID to move before COMMAND, got %v", columnIDs(dashboard.columns))
+	}
+	if !strings.HasSuffix(dashboard.processHeader.Text, "Command") {
+		t.Errorf("Expected header to end with Command, got %q", dashboard.processHeader.Text)
+	}
+
+	// Пока открыт экран настройки, 'q' закрывает его, а не приложение
+	if dashboard.handleKey("q") {
+		t.Error("Expected q to close setup instead of quitting")
+	}
+	if dashboard.showSetup {
+		t.Error("Expected setup to be closed")
+	}
+}
+
+func TestDashboard_SetupKeepsLastColumn(t *testing.T) {
+	dashboard, err := NewDashboardWithUI(NewMockUI())
+	if err != nil {
+		t.Fatalf("Failed to create dashboard: %v", err)
+	}
+	dashboard.columns = columnsByID([]string{"PID"})
+	dashboard.handleKey("<F2>")
+	dashboard.handleKey("<Space>")
+	if len(dashboard.columns) != 1 {
+		t.Errorf("Expected last column to stay, got %v", columnIDs(dashboard.columns))
+	}
+}
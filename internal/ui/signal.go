@@ -0,0 +1,300 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// Ширина меню сигналов и окна подтверждения сигнала
+const (
+	signalMenuWidth = 56
+	confirmWidth    = 60
+)
+
+// signalMenuTitle - заголовок меню сигналов без набранного запроса
+const signalMenuTitle = "Send Signal (type number/name, Space pin, ← cancel)"
+
+// newSignalMenu создает меню сигналов. Строки заполняет updateSignalRows.
+func newSignalMenu() *widgets.List {
+	menu := widgets.NewList()
+	menu.Title = signalMenuTitle
+	menu.TextStyle = ui.NewStyle(ui.ColorWhite)
+	menu.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorYellow)
+	menu.WrapText = false
+	return menu
+}
+
+// orderSignals возвращает сигналы в порядке меню: избранные в порядке
+// настроек, затем остальные по номеру. Избранные, которых нет на текущей ОС,
+// пропускаются.
+func orderSignals(favorites []string) []system.Signal {
+	order := make([]system.Signal, 0, len(system.AvailableSignals))
+	for _, name := range favorites {
+		if sig, ok := system.FindSignal(name); ok {
+			order = append(order, sig)
+		}
+	}
+	for _, sig := range system.AvailableSignals {
+		if !slices.Contains(favorites, sig.Name) {
+			order = append(order, sig)
+		}
+	}
+	return order
+}
+
+// findSignalRow ищет в меню сигнал по номеру или началу имени, с префиксом
+// SIG или без него
+func findSignalRow(signals []system.Signal, query string) (int, bool) {
+	if n, err := strconv.Atoi(query); err == nil {
+		for i, sig := range signals {
+			if sig.Number() == n {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+	query = strings.ToUpper(query)
+	for i, sig := range signals {
+		if strings.HasPrefix(sig.Name, query) || strings.HasPrefix(strings.TrimPrefix(sig.Name, "SIG"), query) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// updateSignalRows перестраивает строки меню сигналов; избранные помечены *
+func (d *Dashboard) updateSignalRows() {
+	d.signalOrder = orderSignals(d.config.FavoriteSignals)
+	rows := make([]string, len(d.signalOrder))
+	for i, sig := range d.signalOrder {
+		mark := " "
+		if slices.Contains(d.config.FavoriteSignals, sig.Name) {
+			mark = "*"
+		}
+		rows[i] = fmt.Sprintf("%s%3d %-11s %-4s %s", mark, sig.Number(), sig.Name, sig.Action, sig.Description)
+	}
+	d.signalMenu.Rows = rows
+}
+
+// openSignalMenu показывает меню сигналов. Курсор стоит на первом избранном
+// сигнале, а без избранных - на SIGTERM.
+func (d *Dashboard) openSignalMenu() {
+	d.updateSignalRows()
+	d.showSignalMenu = true
+	d.menuTargets = d.targets()
+	d.signalQuery = ""
+	d.signalNotFound = false
+	row := 0
+	if len(d.signalOrder) > 0 && !slices.Contains(d.config.FavoriteSignals, d.signalOrder[0].Name) {
+		row, _ = findSignalRow(d.signalOrder, "SIGTERM")
+	}
+	d.selectSignal(row)
+	d.updateSignalTitle()
+}
+
+// closeSignalMenu скрывает меню сигналов
+func (d *Dashboard) closeSignalMenu() {
+	d.showSignalMenu = false
+	d.selectedSignal = 0
+	d.signalQuery = ""
+}
+
+// selectSignal перемещает курсор меню сигналов на строку row в пределах меню
+func (d *Dashboard) selectSignal(row int) {
+	d.selectedSignal = min(max(row, 0), len(d.signalOrder)-1)
+	d.signalMenu.SelectedRow = d.selectedSignal
+}
+
+// handleSignalMenuKey обрабатывает клавиши в меню сигналов. Набранные цифры
+// и буквы переводят курсор на сигнал с таким номером или именем.
+func (d *Dashboard) handleSignalMenuKey(id string) {
+	switch id {
+	case "<Left>", "<Escape>":
+		d.closeSignalMenu()
+		return
+	case "<Up>":
+		d.selectSignal(d.selectedSignal - 1)
+		d.signalQuery = ""
+	case "<Down>":
+		d.selectSignal(d.selectedSignal + 1)
+		d.signalQuery = ""
+	case "<PageUp>":
+		d.selectSignal(d.selectedSignal - d.signalMenu.Inner.Dy())
+		d.signalQuery = ""
+	case "<PageDown>":
+		d.selectSignal(d.selectedSignal + d.signalMenu.Inner.Dy())
+		d.signalQuery = ""
+	case "<Enter>":
+		sig := d.signalOrder[d.selectedSignal]
+		d.closeSignalMenu()
+		d.requestSignal(sig, d.menuTargets)
+		return
+	case "<Space>":
+		d.toggleFavoriteSignal()
+	case "<Backspace>", "<C-<Backspace>>":
+		if d.signalQuery != "" {
+			d.signalQuery = d.signalQuery[:len(d.signalQuery)-1]
+			d.jumpToSignal()
+		}
+	default:
+		if isSignalQueryKey(id) {
+			d.signalQuery += id
+			d.jumpToSignal()
+		}
+	}
+	if d.signalQuery == "" {
+		d.signalNotFound = false
+	}
+	d.updateSignalTitle()
+}
+
+// isSignalQueryKey сообщает, может ли клавиша быть частью номера или имени
+// сигнала, например SIGRTMAX-2
+func isSignalQueryKey(id string) bool {
+	if len(id) != 1 {
+		return false
+	}
+	c := id[0]
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '+' || c == '-'
+}
+
+// jumpToSignal переводит курсор на сигнал, подходящий под набранный запрос
+func (d *Dashboard) jumpToSignal() {
+	if d.signalQuery == "" {
+		return
+	}
+	row, ok := findSignalRow(d.signalOrder, d.signalQuery)
+	d.signalNotFound = !ok
+	if ok {
+		d.selectSignal(row)
+	}
+}
+
+// updateSignalTitle показывает в заголовке меню набранный запрос
+func (d *Dashboard) updateSignalTitle() {
+	if d.signalQuery == "" {
+		d.signalMenu.Title = signalMenuTitle
+		return
+	}
+	d.signalMenu.Title = "Send Signal: " + d.signalQuery + "_"
+	if d.signalNotFound {
+		d.signalMenu.Title += " (not found)"
+	}
+}
+
+// toggleFavoriteSignal закрепляет сигнал под курсором вверху меню или снимает
+// закрепление и сохраняет избранное в настройках. Курсор остается на сигнале.
+func (d *Dashboard) toggleFavoriteSignal() {
+	sig := d.signalOrder[d.selectedSignal]
+	favorites := slices.Clone(d.config.FavoriteSignals)
+	if i := slices.Index(favorites, sig.Name); i >= 0 {
+		favorites = slices.Delete(favorites, i, i+1)
+	} else {
+		favorites = append(favorites, sig.Name)
+	}
+	d.config.FavoriteSignals = favorites
+	d.updateSignalRows()
+	d.selectSignal(slices.IndexFunc(d.signalOrder, func(s system.Signal) bool { return s.Signal == sig.Signal }))
+	d.saveConfig()
+}
+
+// signalConfirm - окно подтверждения сигнала. Показывается перед сигналами,
+// которые нельзя перехватить, и перед сигналами init или самому htop.
+type signalConfirm struct {
+	signal    system.Signal
+	targets   actionTargets
+	protected int // сколько из targets защищены от сигналов без F
+	list      *widgets.List
+}
+
+// requestSignal отправляет сигнал процессам targets, при необходимости сначала
+// запрашивая подтверждение
+func (d *Dashboard) requestSignal(sig system.Signal, targets actionTargets) {
+	if len(targets.processes) == 0 {
+		return
+	}
+	protected := 0
+	for _, p := range targets.processes {
+		if system.CheckSignalTarget(p.PID) != nil {
+			protected++
+		}
+	}
+	if protected == 0 && !sig.Destructive() {
+		d.sendSignal(sig, targets, false)
+		return
+	}
+
+	confirm := &signalConfirm{signal: sig, targets: targets, protected: protected, list: widgets.NewList()}
+	rows := make([]string, 0, len(targets.processes))
+	for _, p := range targets.processes {
+		row := fmt.Sprintf("%7d %-15s %s", p.PID, fitText(p.Name, 15, false), p.User)
+		if err := system.CheckSignalTarget(p.PID); err != nil {
+			row += " (protected: F to include)"
+		}
+		rows = append(rows, row)
+	}
+	confirm.list.Rows = rows
+	confirm.list.Title = fmt.Sprintf("Send %s to %d processes? (y yes, n no)", sig.Name, len(targets.processes))
+	if protected > 0 {
+		confirm.list.Title = fmt.Sprintf("Send %s to %d processes? (y yes, F force, n no)", sig.Name, len(targets.processes))
+	}
+	confirm.list.TextStyle = ui.NewStyle(ui.ColorWhite)
+	confirm.list.SelectedRowStyle = confirm.list.TextStyle
+	confirm.list.BorderStyle.Fg = ui.ColorRed
+	confirm.list.WrapText = false
+	if d.options.Monochrome {
+		plain := ui.NewStyle(ui.ColorClear)
+		confirm.list.BorderStyle = plain
+		confirm.list.TitleStyle = plain
+		confirm.list.TextStyle = plain
+		confirm.list.SelectedRowStyle = plain
+	}
+	d.confirm = confirm
+}
+
+// handleConfirmKey обрабатывает клавиши в окне подтверждения сигнала:
+// y отправляет сигнал незащищенным процессам, F - всем
+func (d *Dashboard) handleConfirmKey(id string) {
+	confirm := d.confirm
+	switch id {
+	case "y", "Y":
+		d.confirm = nil
+		d.sendSignal(confirm.signal, confirm.targets, false)
+	case "F":
+		d.confirm = nil
+		d.sendSignal(confirm.signal, confirm.targets, true)
+	case "n", "N", "<Escape>", "<Left>", "q":
+		d.confirm = nil
+	case "<Up>":
+		confirm.list.ScrollUp()
+	case "<Down>":
+		confirm.list.ScrollDown()
+	}
+}
+
+// sendSignal отправляет сигнал процессам. Защищенным процессам сигнал
+// отправляется, только если force == true. Источник сверяет время запуска
+// каждого процесса, чтобы не отправить сигнал процессу, занявшему PID.
+func (d *Dashboard) sendSignal(sig system.Signal, targets actionTargets, force bool) {
+	d.runAction("send "+sig.Name+" to", targets, func(p system.ProcessInfo) error {
+		if !force {
+			if err := system.CheckSignalTarget(p.PID); err != nil {
+				return err
+			}
+		}
+		return d.source.SendSignal(p.PID, p.StartTime, sig.Signal)
+	})
+}
+
+// updateConfirmPosition располагает окно подтверждения у курсора
+func (d *Dashboard) updateConfirmPosition() {
+	rect := d.menuRect(confirmWidth, len(d.confirm.list.Rows))
+	d.confirm.list.SetRect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y)
+}
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// selectSignal открывает меню сигналов и выбирает сигнал sig
+func selectSignal(t *testing.T, d *Dashboard, sig syscall.Signal) {
+	t.Helper()
+	d.handleKey("<Right>")
+	for i, s := range d.signalOrder {
+		if s.Signal == sig {
+			d.selectSignal(i)
+			return
+		}
+	}
+	t.Fatalf("Signal %v is not in the menu", sig)
+}
+
+func TestDashboard_SignalPassesStartTime(t *testing.T) {
+	source := newFakeSource()
+	start := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	source.processes[1].StartTime = start
+	dashboard := newPriorityDashboard(t, source)
+
+	// Источник получает время запуска из списка, чтобы сверить его перед отправкой
+	selectSignal(t, dashboard, syscall.SIGTERM)
+	dashboard.handleKey("<Enter>")
+	if dashboard.confirm != nil {
+		t.Fatal("Expected SIGTERM to be sent without confirmation")
+	}
+	if len(source.signalStarts) != 1 || !source.signalStarts[0].Equal(start) {
+		t.Errorf("Expected start time %v passed to the source, got %v", start, source.signalStarts)
+	}
+}
+
+func TestDashboard_KillNeedsConfirmation(t *testing.T) {
+	source := newFakeSource()
+	dashboard := newPriorityDashboard(t, source)
+
+	selectSignal(t, dashboard, syscall.SIGKILL)
+	dashboard.handleKey("<Enter>")
+	if dashboard.confirm == nil || len(source.signals) != 0 {
+		t.Fatalf("Expected SIGKILL to wait for confirmation, sent %v", source.signals)
+	}
+	if !strings.HasPrefix(dashboard.confirm.list.Title, "Send SIGKILL to 1 processes? (y yes, n no)") {
+		t.Errorf("Unexpected confirmation title %q", dashboard.confirm.list.Title)
+	}
+	dashboard.handleKey("n")
+	if dashboard.confirm != nil || len(source.signals) != 0 {
+		t.Fatal("Expected n to cancel SIGKILL")
+	}
+
+	selectSignal(t, dashboard, syscall.SIGKILL)
+	dashboard.handleKey("<Enter>")
+	dashboard.handleKey("y")
+	if len(source.signals) != 1 || source.signals[0] != (sentSignal{200, syscall.SIGKILL}) {
+		t.Errorf("Expected SIGKILL to PID 200 after confirmation, got %v", source.signals)
+	}
+}
+
+func TestDashboard_ProtectedProcesses(t *testing.T) {
+	source := newFakeSource()
+	source.processes = append(source.processes, system.ProcessInfo{PID: int32(os.Getpid()), Name: "htop", CPU: 10})
+	dashboard := newPriorityDashboard(t, source)
+	dashboard.handleKey("*")
+
+	// init и сам htop отмечены вместе с обычным процессом
+	selectSignal(t, dashboard, syscall.SIGHUP)
+	dashboard.handleKey("<Enter>")
+	if dashboard.confirm == nil || dashboard.confirm.protected != 2 {
+		t.Fatalf("Expected confirmation for 2 protected processes, got %+v", dashboard.confirm)
+	}
+	if !strings.Contains(dashboard.confirm.list.Title, "F force") {
+		t.Errorf("Expected force hint in title, got %q", dashboard.confirm.list.Title)
+	}
+
+	// y отправляет сигнал только незащищенным процессам
+	dashboard.handleKey("y")
+	if len(source.signals) != 1 || source.signals[0].pid != 200 {
+		t.Errorf("Expected SIGHUP to PID 200 only, got %v", source.signals)
+	}
+	if dashboard.summary == nil || !strings.Contains(dashboard.summary.Title, "1 ok, 2 failed") {
+		t.Fatalf("Expected refused processes in the summary, got %+v", dashboard.summary)
+	}
+	dashboard.handleKey("<Escape>")
+
+	// F отправляет всем
+	source.signals = nil
+	selectSignal(t, dashboard, syscall.SIGHUP)
+	dashboard.handleKey("<Enter>")
+	dashboard.handleKey("F")
+	if len(source.signals) != 3 {
+		t.Errorf("Expected F to signal all tagged processes, got %v", source.signals)
+	}
+}
+
+func TestDashboard_SignalMenuStartsOnFavorite(t *testing.T) {
+	dashboard := newPriorityDashboard(t, newFakeSource())
+
+	// Избранные по умолчанию закреплены вверху, курсор на SIGTERM
+	dashboard.handleKey("<Right>")
+	if got := dashboard.signalOrder[dashboard.selectedSignal].Name; got != "SIGTERM" {
+		t.Errorf("Expected cursor on SIGTERM, got %s", got)
+	}
+	if !strings.HasPrefix(dashboard.signalMenu.Rows[0], "* 15 SIGTERM") {
+		t.Errorf("Expected pinned SIGTERM row first, got %q", dashboard.signalMenu.Rows[0])
+	}
+	if len(dashboard.signalOrder) != len(system.AvailableSignals) {
+		t.Errorf("Expected every signal in the menu once, got %d of %d", len(dashboard.signalOrder), len(system.AvailableSignals))
+	}
+
+	// Без избранных курсор тоже на SIGTERM, а не на первом по номеру SIGHUP
+	dashboard.handleKey("<Escape>")
+	dashboard.config.FavoriteSignals = nil
+	dashboard.handleKey("<Right>")
+	if got := dashboard.signalOrder[dashboard.selectedSignal].Name; got != "SIGTERM" {
+		t.Errorf("Expected cursor on SIGTERM without favorites, got %s", got)
+	}
+}
+
+func TestDashboard_SignalMenuJump(t *testing.T) {
+	source := newFakeSource()
+	dashboard := newPriorityDashboard(t, source)
+	dashboard.handleKey("<Right>")
+
+	selected := func() string { return dashboard.signalOrder[dashboard.selectedSignal].Name }
+	for _, key := range []string{"2", "8"} {
+		dashboard.handleKey(key)
+	}
+	if selected() != "SIGWINCH" || dashboard.signalMenu.Title != "Send Signal: 28_" {
+		t.Errorf("Expected 28 to select SIGWINCH, got %s, title %q", selected(), dashboard.signalMenu.Title)
+	}
+
+	dashboard.handleKey("<Backspace>")
+	dashboard.handleKey("<Backspace>")
+	for _, key := range []string{"t", "s", "t"} {
+		dashboard.handleKey(key)
+	}
+	if selected() != "SIGTSTP" {
+		t.Errorf("Expected \"tst\" to select SIGTSTP, got %s", selected())
+	}
+
+	// Ненайденный запрос оставляет курсор на месте
+	dashboard.handleKey("x")
+	if selected() != "SIGTSTP" || !strings.HasSuffix(dashboard.signalMenu.Title, "(not found)") {
+		t.Errorf("Expected cursor to stay on SIGTSTP, got %s, title %q", selected(), dashboard.signalMenu.Title)
+	}
+
+	dashboard.handleKey("<Enter>")
+	if dashboard.showSignalMenu || len(source.signals) != 1 || source.signals[0].sig != syscall.SIGTSTP {
+		t.Errorf("Expected SIGTSTP to be sent, got %v", source.signals)
+	}
+}
+
+func TestDashboard_PinSignal(t *testing.T) {
+	dashboard := newPriorityDashboard(t, newFakeSource())
+	dashboard.config.FavoriteSignals = []string{"SIGTERM", "SIGNOPE"}
+	dashboard.handleKey("<Right>")
+
+	// Избранное с другой ОС пропускается в меню, но остается в настройках
+	for _, key := range []string{"u", "s", "r", "2", "<Space>"} {
+		dashboard.handleKey(key)
+	}
+	if got := strings.Join(dashboard.config.FavoriteSignals, ","); got != "SIGTERM,SIGNOPE,SIGUSR2" {
+		t.Errorf("Expected SIGUSR2 to be pinned, got %s", got)
+	}
+	if dashboard.signalOrder[1].Name != "SIGUSR2" || dashboard.selectedSignal != 1 {
+		t.Errorf("Expected SIGUSR2 pinned second with the cursor on it, got %s at %d",
+			dashboard.signalOrder[1].Name, dashboard.selectedSignal)
+	}
+
+	dashboard.handleKey("<Space>")
+	if got := strings.Join(dashboard.config.FavoriteSignals, ","); got != "SIGTERM,SIGNOPE" {
+		t.Errorf("Expected SIGUSR2 to be unpinned, got %s", got)
+	}
+	if got := dashboard.signalOrder[dashboard.selectedSignal].Name; got != "SIGUSR2" {
+		t.Errorf("Expected cursor to follow unpinned SIGUSR2, got %s", got)
+	}
+}
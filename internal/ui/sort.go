@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// SortKey определяет колонку, по которой сортируется список процессов.
+// Значение - индекс в sortFields.
+type SortKey int
+
+// Режимы сортировки, на которые ссылается код
+var (
+	SortByCPU    = mustSortKey("CPU")
+	SortByMemory = mustSortKey("MEM")
+	SortByPID    = mustSortKey("PID")
+	SortByName   = mustSortKey("NAME")
+	SortByStatus = mustSortKey("STATE")
+)
+
+// sortField описывает режим сортировки: подпись, горячую клавишу и функцию сравнения
+type sortField struct {
+	Name        string
+	Key         string // пустая строка - режим доступен только через переключение
+	DefaultDesc bool   // направление по умолчанию при выборе режима
+	compare     func(a, b system.ProcessInfo) int
+}
+
+// sortFields содержит режимы сортировки по всем колонкам реестра в его порядке:
+// индекс совпадает с SortKey, а Name - с идентификатором колонки в настройках.
+// Режимы строятся из columnRegistry, поэтому у каждой колонки есть сортировка.
+var sortFields = func() []sortField {
+	fields := make([]sortField, len(columnRegistry))
+	for i, c := range columnRegistry {
+		fields[i] = sortField{Name: c.ID, Key: c.SortKey, DefaultDesc: c.DefaultDesc, compare: c.compare}
+	}
+	return fields
+}()
+
+// mustSortKey возвращает режим сортировки по идентификатору колонки
+func mustSortKey(name string) SortKey {
+	key, ok := sortKeyByName(name)
+	if !ok {
+		panic("unknown sort column " + name)
+	}
+	return key
+}
+
+// String возвращает подпись режима сортировки
+func (k SortKey) String() string {
+	if k < 0 || int(k) >= len(sortFields) {
+		return "?"
+	}
+	return sortFields[k].Name
+}
+
+// sortKeyByHotkey возвращает режим сортировки, привязанный к клавише
+func sortKeyByHotkey(key string) (SortKey, bool) {
+	for i, f := range sortFields {
+		if f.Key != "" && f.Key == key {
+			return SortKey(i), true
+		}
+	}
+	return 0, false
+}
+
+// sortKeyByName возвращает режим сортировки по подписи (как в настройках)
+func sortKeyByName(name string) (SortKey, bool) {
+	for i, f := range sortFields {
+		if f.Name == name {
+			return SortKey(i), true
+		}
+	}
+	return 0, false
+}
+
+// sortProcesses сортирует процессы на месте. При равенстве значений
+// процессы упорядочиваются по PID, чтобы порядок не "прыгал" между обновлениями.
+func sortProcesses(processes []system.ProcessInfo, key SortKey, desc bool) {
+	if key < 0 || int(key) >= len(sortFields) {
+		return
+	}
+	compare := sortFields[key].compare
+	slices.SortFunc(processes, func(a, b system.ProcessInfo) int {
+		c := compare(a, b)
+		if desc {
+			c = -c
+		}
+		if c == 0 {
+			c = cmp.Compare(a.PID, b.PID)
+		}
+		return c
+	})
+}
+
+// setSortKey переключает режим сортировки. Повторный выбор текущего режима
+// меняет направление на противоположное.
+func (d *Dashboard) setSortKey(key SortKey) {
+	if key == d.sortKey {
+		d.sortDesc = !d.sortDesc
+	} else {
+		d.sortKey = key
+		d.sortDesc = sortFields[key].DefaultDesc
+	}
+	d.refreshProcessList()
+	d.saveSort()
+}
+
+// nextSortKey переключает сортировку на следующий режим по кругу
+func (d *Dashboard) nextSortKey() {
+	d.setSortKey(SortKey((int(d.sortKey) + 1) % len(sortFields)))
+}
+
+// invertSort меняет направление сортировки
+func (d *Dashboard) invertSort() {
+	d.sortDesc = !d.sortDesc
+	d.refreshProcessList()
+	d.saveSort()
+}
+
+// saveSort сохраняет в настройках сортировку, выбранную в интерфейсе
+func (d *Dashboard) saveSort() {
+	d.config.Sort = config.Sort{Column: d.sortKey.String(), Descending: d.sortDesc}
+	d.saveConfig()
+}
+
+// sortIndicator возвращает подпись текущей сортировки для заголовка списка
+func (d *Dashboard) sortIndicator() string {
+	arrow := "▲"
+	if d.sortDesc {
+		arrow = "▼"
+	}
+	return d.sortKey.String() + " " + arrow
+}
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+func testProcesses() []system.ProcessInfo {
+	return []system.ProcessInfo{
+		{PID: 10, Name: "bash", CPU: 1.0, Memory: 0.5, Status: "S"},
+		{PID: 20, Name: "Xorg", CPU: 12.5, Memory: 3.0, Status: "R"},
+		{PID: 30, Name: "chrome", CPU: 40.0, Memory: 25.0, Status: "S"},
+		{PID: 5, Name: "init", CPU: 0.0, Memory: 0.1, Status: "S"},
+	}
+}
+
+func pids(processes []system.ProcessInfo) []int32 {
+	result := make([]int32, len(processes))
+	for i, p := range processes {
+		result[i] = p.PID
+	}
+	return result
+}
+
+func equalPIDs(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSortProcesses(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      SortKey
+		desc     bool
+		expected []int32
+	}{
+		{"CPU descending", SortByCPU, true, []int32{30, 20, 10, 5}},
+		{"CPU ascending", SortByCPU, false, []int32{5, 10, 20, 30}},
+		{"Memory descending", SortByMemory, true, []int32{30, 20, 10, 5}},
+		{"PID ascending", SortByPID, false, []int32{5, 10, 20, 30}},
+		{"Name ascending ignores case", SortByName, false, []int32{10, 30, 5, 20}},
+		{"Status ascending, ties by PID", SortByStatus, false, []int32{20, 5, 10, 30}},
+		{"Status descending, ties by PID", SortByStatus, true, []int32{5, 10, 30, 20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processes := testProcesses()
+			sortProcesses(processes, tt.key, tt.desc)
+			if got := pids(processes); !equalPIDs(got, tt.expected) {
+				t.Errorf("sortProcesses(%v, desc=%v) = %v, want %v", tt.key, tt.desc, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSortKeyByHotkey(t *testing.T) {
+	if key, ok := sortKeyByHotkey("P"); !ok || key != SortByCPU {
+		t.Errorf("Expected P to select CPU sort, got %v (%v)", key, ok)
+	}
+	if key, ok := sortKeyByHotkey("M"); !ok || key != SortByMemory {
+		t.Errorf("Expected M to select memory sort, got %v (%v)", key, ok)
+	}
+	if _, ok := sortKeyByHotkey("x"); ok {
+		t.Error("Expected no sort mode bound to x")
+	}
+}
+
+func TestDashboard_SortKeepsSelectedPID(t *testing.T) {
+	dashboard, err := NewDashboardWithUI(NewMockUI())
+	if err != nil {
+		t.Fatalf("Failed to create dashboard: %v", err)
+	}
+
+	dashboard.allProcesses = testProcesses()
+	dashboard.refreshProcessList()
+
+	// По умолчанию сортировка по CPU по убыванию: chrome первый
+	if dashboard.processes[0].PID != 30 {
+		t.Fatalf("Expected PID 30 at the top, got %d", dashboard.processes[0].PID)
+	}
+
+	// Выбираем Xorg (PID 20) во второй строке
+	dashboard.handleKey("<Down>")
+	if dashboard.selectedPID != 20 {
+		t.Fatalf("Expected PID 20 to be selected, got %d", dashboard.selectedPID)
+	}
+
+	// Переключаемся на сортировку по PID: курсор должен остаться на PID 20
+	dashboard.handleKey("N")
+	if dashboard.sortKey != SortByPID || dashboard.sortDesc {
+		t.Errorf("Expected ascending PID sort, got %v desc=%v", dashboard.sortKey, dashboard.sortDesc)
+	}
+	if got := dashboard.processes[dashboard.selectedRow].PID; got != 20 {
+		t.Errorf("Expected selection to follow PID 20, got %d", got)
+	}
+	if dashboard.processList.SelectedRow != 2 {
+		t.Errorf("Expected list row 2 to be selected, got %d", dashboard.processList.SelectedRow)
+	}
+
+	// Повторное нажатие меняет направление
+	dashboard.handleKey("N")
+	if !dashboard.sortDesc {
+		t.Error("Expected repeated hotkey to invert sort direction")
+	}
+	if got := dashboard.processes[dashboard.selectedRow].PID; got != 20 {
+		t.Errorf("Expected selection to follow PID 20 after invert, got %d", got)
+	}
+}
+
+func TestDashboard_SortSelectionFallsBackWhenProcessExits(t *testing.T) {
+	dashboard, err := NewDashboardWithUI(NewMockUI())
+	if err != nil {
+		t.Fatalf("Failed to create dashboard: %v", err)
+	}
+
+	dashboard.allProcesses = testProcesses()
+	dashboard.refreshProcessList()
+	dashboard.selectRow(3)
+
+	// Выбранный процесс завершился, список стал короче
+	dashboard.allProcesses = testProcesses()[:2]
+	dashboard.refreshProcessList()
+	if dashboard.selectedRow != 1 {
+		t.Errorf("Expected cursor to be clamped to row 1, got %d", dashboard.selectedRow)
+	}
+}
+
+func TestDashboard_NextSortKeyCycles(t *testing.T) {
+	dashboard, err := NewDashboardWithUI(NewMockUI())
+	if err != nil {
+		t.Fatalf("Failed to create dashboard: %v", err)
+	}
+
+	for i := 0; i < len(sortFields); i++ {
+		dashboard.handleKey(">")
+	}
+	if dashboard.sortKey != SortByCPU {
+		t.Errorf("Expected sort mode to cycle back to CPU, got %v", dashboard.sortKey)
+	}
+}
+
+func TestSortFieldsCoverAllColumns(t *testing.T) {
+	// Каждая колонка реестра доступна для сортировки, в том числе через -s
+	for _, c := range columnRegistry {
+		key, ok := sortKeyByName(c.ID)
+		if !ok || sortFields[key].compare == nil {
+			t.Errorf("Column %s has no sort mode", c.ID)
+		}
+	}
+
+	processes := []system.ProcessInfo{
+		{PID: 1, ReadBytes: 100, WriteBytes: 900, Cgroup: "/b", IOPriority: system.IOPriority{Class: system.IOClassIdle}},
+		{PID: 2, ReadBytes: 700, WriteBytes: 10, Cgroup: "/a", IOPriority: system.IOPriority{Class: system.IOClassRealtime, Level: 3}},
+		{PID: 3, ReadBytes: 300, WriteBytes: 50, Cgroup: "/c", Nice: 19},
+	}
+	tests := []struct {
+		column string
+		desc   bool
+		want   []int32
+	}{
+		{"IO_READ", true, []int32{2, 3, 1}},
+		{"IO_WRITE", true, []int32{1, 3, 2}},
+		{"CGROUP", false, []int32{2, 1, 3}},
+		// Realtime выше best-effort, который следует из nice, idle - ниже всех
+		{"IO_PRIORITY", false, []int32{2, 3, 1}},
+	}
+	for _, tt := range tests {
+		key, _ := sortKeyByName(tt.column)
+		if tt.desc != sortFields[key].DefaultDesc {
+			t.Errorf("Unexpected default direction for %s", tt.column)
+		}
+		sortProcesses(processes, key, tt.desc)
+		if got := pids(processes); !equalPIDs(got, tt.want) {
+			t.Errorf("Sort by %s = %v, want %v", tt.column, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bonefabric/htop/internal/config"
+)
+
+func TestDashboard_ProcFixture(t *testing.T) {
+	root := filepath.Join("..", "system", "testdata", "proc", "basic")
+	dashboard, err := newDashboard(NewMockUI(), config.Default(), Options{ProcRoot: root, SortColumn: "PID"})
+	if err != nil {
+		t.Fatalf("newDashboard() вернул ошибку: %v", err)
+	}
+	if len(dashboard.cpuCharts) != 4 {
+		t.Fatalf("Expected a gauge per fixture CPU, got %d", len(dashboard.cpuCharts))
+	}
+
+	if err := dashboard.update(); err != nil {
+		t.Fatalf("update() вернул ошибку: %v", err)
+	}
+	for i, chart := range dashboard.cpuCharts {
+		if chart.Percent != 20 {
+			t.Errorf("Expected CPU %d at 20%%, got %d", i, chart.Percent)
+		}
+	}
+	if dashboard.memChart.Percent != 50 {
+		t.Errorf("Expected memory at 50%%, got %d", dashboard.memChart.Percent)
+	}
+	if !equalPIDs(pids(dashboard.processes), []int32{1, 2, 3, 100, 101}) {
+		t.Errorf("Expected fixture processes, got %v", pids(dashboard.processes))
+	}
+}
+
+func TestNewDashboard_MissingProcRoot(t *testing.T) {
+	if _, err := newDashboard(NewMockUI(), config.Default(), Options{ProcRoot: "no-such-procfs"}); err == nil {
+		t.Error("Expected error for missing procfs root")
+	}
+}
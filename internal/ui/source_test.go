@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/bonefabric/htop/internal/config"
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// sentSignal - сигнал, отправленный через fakeSource
+type sentSignal struct {
+	pid int32
+	sig syscall.Signal
+}
+
+// fakeSource - system.Source с заранее заданными данными
+type fakeSource struct {
+	cores     int
+	cpu       []float64
+	memory    mem.VirtualMemoryStat
+	processes []system.ProcessInfo
+	// Ошибка получения списка процессов
+	processErr error
+	signalErr  error
+	signals    []sentSignal
+	// Время запуска, с которым отправлялись сигналы
+	signalStarts []time.Time
+	details      map[int32]*system.ProcessDetail
+	// Если задан, чтение сведений ждет его закрытия или отмены контекста
+	detailGate  chan struct{}
+	detailReads atomic.Int32
+	// Приоритеты, заданные через SetNice и SetIOPriority
+	nices        map[int32]int32
+	ioPriorities map[int32]system.IOPriority
+	priorityErr  error
+	// Привязка к ядрам по PID и признак, что она задана всем потокам
+	affinity        map[int32][]int
+	affinityThreads bool
+}
+
+func (f *fakeSource) CPUCount() (int, error) { return f.cores, nil }
+
+func (f *fakeSource) CPUPercent(ctx context.Context) ([]float64, error) { return f.cpu, nil }
+
+func (f *fakeSource) Memory(ctx context.Context) (*mem.VirtualMemoryStat, error) {
+	memory := f.memory
+	return &memory, nil
+}
+
+func (f *fakeSource) Processes(ctx context.Context) ([]system.ProcessInfo, error) {
+	return f.processes, f.processErr
+}
+
+func (f *fakeSource) SendSignal(pid int32, start time.Time, sig syscall.Signal) error {
+	f.signals = append(f.signals, sentSignal{pid, sig})
+	f.signalStarts = append(f.signalStarts, start)
+	return f.signalErr
+}
+
+func (f *fakeSource) ProcessDetail(ctx context.Context, pid int32) (*system.ProcessDetail, error) {
+	f.detailReads.Add(1)
+	if f.detailGate != nil {
+		select {
+		case <-f.detailGate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if d, ok := f.details[pid]; ok {
+		return d, nil
+	}
+	return nil, errors.New("no such process")
+}
+
+func (f *fakeSource) SetNice(pid int32, nice int32) error {
+	if f.priorityErr != nil {
+		return f.priorityErr
+	}
+	if f.nices == nil {
+		f.nices = make(map[int32]int32)
+	}
+	f.nices[pid] = nice
+	return nil
+}
+
+func (f *fakeSource) SetIOPriority(pid int32, prio system.IOPriority) error {
+	if f.priorityErr != nil {
+		return f.priorityErr
+	}
+	if f.ioPriorities == nil {
+		f.ioPriorities = make(map[int32]system.IOPriority)
+	}
+	f.ioPriorities[pid] = prio
+	return nil
+}
+
+func (f *fakeSource) Affinity(pid int32) ([]int, error) {
+	if cpus, ok := f.affinity[pid]; ok {
+		return cpus, nil
+	}
+	return nil, errors.New("no such process")
+}
+
+func (f *fakeSource) SetAffinity(pid int32, cpus []int, allThreads bool) error {
+	if f.affinity == nil {
+		f.affinity = make(map[int32][]int)
+	}
+	f.affinity[pid] = cpus
+	f.affinityThreads = allThreads
+	return nil
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		cores:  3,
+		cpu:    []float64{10, 55, 95},
+		memory: mem.VirtualMemoryStat{Total: 8 << 30, Used: 2 << 30, Available: 6 << 30, UsedPercent: 25},
+		processes: []system.ProcessInfo{
+			{PID: 1, Name: "init", User: "root", CPU: 0.5},
+			{PID: 200, Name: "worker", User: "alice", CPU: 80},
+		},
+	}
+}
+
+func TestDashboard_FakeSource(t *testing.T) {
+	source := newFakeSource()
+	dashboard, err := NewDashboardWithSource(NewMockUI(), source, config.Default())
+	if err != nil {
+		t.Fatalf("NewDashboardWithSource() вернул ошибку: %v", err)
+	}
+	if len(dashboard.cpuCharts) != 3 {
+		t.Fatalf("Expected a gauge per fake core, got %d", len(dashboard.cpuCharts))
+	}
+
+	if err := dashboard.update(); err != nil {
+		t.Fatalf("update() вернул ошибку: %v", err)
+	}
+	if dashboard.cpuCharts[1].Percent != 55 || dashboard.memChart.Percent != 25 {
+		t.Errorf("Expected meters from source, got CPU1=%d mem=%d",
+			dashboard.cpuCharts[1].Percent, dashboard.memChart.Percent)
+	}
+	if !equalPIDs(pids(dashboard.processes), []int32{200, 1}) {
+		t.Errorf("Expected processes from source sorted by CPU, got %v", pids(dashboard.processes))
+	}
+
+	// Сигнал уходит в источник, а не в процесс текущей машины
+	dashboard.handleKey("<Right>")
+	dashboard.handleKey("<Enter>")
+	if len(source.signals) != 1 || source.signals[0] != (sentSignal{200, syscall.SIGTERM}) {
+		t.Errorf("Expected SIGTERM to PID 200 via source, got %v", source.signals)
+	}
+}
+
+func TestDashboard_FakeSourceErrors(t *testing.T) {
+	source := newFakeSource()
+	source.signalErr = errors.New("operation not permitted")
+	dashboard, err := NewDashboardWithSource(NewMockUI(), source, config.Default())
+	if err != nil {
+		t.Fatalf("NewDashboardWithSource() вернул ошибку: %v", err)
+	}
+	if err := dashboard.update(); err != nil {
+		t.Fatalf("update() вернул ошибку: %v", err)
+	}
+
+	dashboard.handleKey("<Right>")
+	if quit := dashboard.handleKey("<Enter>"); quit || dashboard.showSignalMenu {
+		t.Error("Expected signal menu to close without quitting on signal error")
+	}
+}
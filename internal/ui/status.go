@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+)
+
+// severity - важность сообщения в строке состояния
+type severity int
+
+const (
+	severityInfo severity = iota
+	severityWarning
+	severityError
+)
+
+// String возвращает подпись важности для строки состояния и истории
+func (s severity) String() string {
+	switch s {
+	case severityWarning:
+		return "warning"
+	case severityError:
+		return "error"
+	}
+	return "info"
+}
+
+// timeout возвращает, сколько сообщение показывается в строке состояния.
+// Ошибки держатся дольше, чтобы их успели прочитать.
+func (s severity) timeout() time.Duration {
+	switch s {
+	case severityWarning:
+		return 10 * time.Second
+	case severityError:
+		return 30 * time.Second
+	}
+	return 5 * time.Second
+}
+
+// maxMessages - сколько сообщений хранится в истории
+const maxMessages = 200
+
+// statusMessage - сообщение в строке состояния и истории
+type statusMessage struct {
+	time     time.Time
+	severity severity
+	text     string
+	repeats  int // Сколько раз подряд пришло то же сообщение
+}
+
+// String форматирует сообщение для строки состояния и истории
+func (m statusMessage) String() string {
+	text := fmt.Sprintf("%s %-7s %s", m.time.Format("15:04:05"), m.severity, m.text)
+	if m.repeats > 1 {
+		text += fmt.Sprintf(" (x%d)", m.repeats)
+	}
+	return text
+}
+
+// newStatusLine создает строку состояния под списком процессов
+func newStatusLine() *widgets.Paragraph {
+	line := widgets.NewParagraph()
+	line.Border = false
+	return line
+}
+
+// report показывает сообщение в строке состояния и добавляет его в историю.
+// Повтор последнего сообщения, например ошибки при каждом обновлении, не
+// засоряет историю, а увеличивает счетчик. В пакетном режиме экрана нет,
+// и сообщение пишется в журнал.
+func (d *Dashboard) report(sev severity, format string, args ...any) {
+	text := sanitize(fmt.Sprintf(format, args...))
+	if _, ok := d.ui.(headlessUI); ok {
+		log.Printf("%s: %s", sev, text)
+	}
+
+	now := time.Now()
+	if n := len(d.messages); n > 0 && d.messages[n-1].text == text && d.messages[n-1].severity == sev {
+		d.messages[n-1].time = now
+		d.messages[n-1].repeats++
+	} else {
+		d.messages = append(d.messages, statusMessage{time: now, severity: sev, text: text, repeats: 1})
+		if len(d.messages) > maxMessages {
+			d.messages = d.messages[len(d.messages)-maxMessages:]
+		}
+	}
+	d.statusShown = true
+	d.updateStatusLine()
+	if d.history != nil {
+		d.updateHistoryRows()
+	}
+
+	if !d.statusTimer.Stop() {
+		select {
+		case <-d.statusTimer.C:
+		default:
+		}
+	}
+	d.statusTimer.Reset(sev.timeout())
+}
+
+// newStatusTimer создает остановленный таймер, скрывающий сообщение в строке
+// состояния. Таймер создается вместе с Dashboard, чтобы скрывались и
+// сообщения, показанные до запуска Run.
+func newStatusTimer() *time.Timer {
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	return timer
+}
+
+// expireStatus убирает сообщение из строки состояния по таймеру; в истории оно остается
+func (d *Dashboard) expireStatus() {
+	d.statusShown = false
+	d.updateStatusLine()
+}
+
+// updateStatusLine показывает последнее сообщение цветом его важности
+func (d *Dashboard) updateStatusLine() {
+	if !d.statusShown || len(d.messages) == 0 {
+		d.statusLine.Text = ""
+		return
+	}
+	m := d.messages[len(d.messages)-1]
+	d.statusLine.Text = m.String() + " (L messages)"
+	switch {
+	case d.options.Monochrome && m.severity == severityError:
+		d.statusLine.TextStyle = ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierBold)
+	case d.options.Monochrome:
+		d.statusLine.TextStyle = ui.NewStyle(ui.ColorClear)
+	case m.severity == severityError:
+		d.statusLine.TextStyle = ui.NewStyle(ui.ColorRed, ui.ColorClear, ui.ModifierBold)
+	case m.severity == severityWarning:
+		d.statusLine.TextStyle = ui.NewStyle(ui.ColorYellow)
+	default:
+		d.statusLine.TextStyle = ui.NewStyle(ui.ColorGreen)
+	}
+}
+
+// openHistory показывает историю сообщений с курсором на последнем
+func (d *Dashboard) openHistory() {
+	d.history = widgets.NewList()
+	d.history.Title = "Messages (↑/↓ scroll, Esc close)"
+	d.history.TextStyle = ui.NewStyle(ui.ColorWhite)
+	d.history.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorYellow)
+	d.history.WrapText = false
+	if d.options.Monochrome {
+		plain := ui.NewStyle(ui.ColorClear)
+		d.history.BorderStyle = plain
+		d.history.TitleStyle = plain
+		d.history.TextStyle = plain
+		d.history.SelectedRowStyle = ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierReverse)
+	}
+	d.updateHistoryRows()
+	d.history.SelectedRow = len(d.history.Rows) - 1
+}
+
+// updateHistoryRows заполняет окно истории сообщениями, последнее внизу
+func (d *Dashboard) updateHistoryRows() {
+	if len(d.messages) == 0 {
+		d.history.Rows = []string{"No messages"}
+		return
+	}
+	follow := d.history.SelectedRow >= len(d.history.Rows)-1
+	rows := make([]string, len(d.messages))
+	for i, m := range d.messages {
+		rows[i] = m.String()
+	}
+	d.history.Rows = rows
+	// Курсор на последнем сообщении следует за новыми
+	if follow {
+		d.history.SelectedRow = len(rows) - 1
+	}
+	d.history.SelectedRow = min(d.history.SelectedRow, len(rows)-1)
+}
+
+// handleHistoryKey обрабатывает клавиши в окне истории сообщений
+func (d *Dashboard) handleHistoryKey(id string) {
+	switch id {
+	case "<Escape>", "<Left>", "q", "L":
+		d.history = nil
+	case "<Up>":
+		d.history.ScrollUp()
+	case "<Down>":
+		d.history.ScrollDown()
+	case "<PageUp>":
+		d.history.ScrollPageUp()
+	case "<PageDown>":
+		d.history.ScrollPageDown()
+	case "<Home>":
+		d.history.ScrollTop()
+	case "<End>":
+		d.history.ScrollBottom()
+	}
+}
+
+// updateHistoryPosition располагает окно истории поверх списка процессов
+func (d *Dashboard) updateHistoryPosition() {
+	rect := d.processList.GetRect()
+	d.history.SetRect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y)
+}
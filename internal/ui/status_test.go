@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+func TestDashboard_SignalOutcomeInStatusLine(t *testing.T) {
+	source := newFakeSource()
+	dashboard := newPriorityDashboard(t, source)
+
+	dashboard.handleKey("<Right>")
+	dashboard.handleKey("<Enter>")
+	if text := dashboard.statusLine.Text; !strings.Contains(text, "info") ||
+		!strings.Contains(text, "Send SIGTERM to process 200 (worker): ok") {
+		t.Errorf("Expected success in the status line, got %q", text)
+	}
+
+	// Ошибка видна в интерфейсе, а не в журнале под экраном termui
+	source.signalErr = errors.New("operation not permitted")
+	dashboard.handleKey("<Right>")
+	dashboard.handleKey("<Enter>")
+	if text := dashboard.statusLine.Text; !strings.Contains(text, "error") ||
+		!strings.Contains(text, "Failed to send SIGTERM to process 200: operation not permitted") {
+		t.Errorf("Expected failure in the status line, got %q", text)
+	}
+	if len(dashboard.messages) != 2 {
+		t.Errorf("Expected both outcomes in the history, got %v", dashboard.messages)
+	}
+}
+
+func TestDashboard_ProcessErrorInStatusLine(t *testing.T) {
+	dashboard := newPriorityDashboard(t, newFakeSource())
+
+	// Ошибка при каждом обновлении не засоряет историю
+	for i := 0; i < 3; i++ {
+		dashboard.apply(system.Snapshot{CPU: []float64{1}, Memory: dashboard.memInfo,
+			ProcessErr: errors.New("permission denied")})
+	}
+	if len(dashboard.messages) != 1 || dashboard.messages[0].repeats != 3 {
+		t.Fatalf("Expected one repeated message, got %+v", dashboard.messages)
+	}
+	if text := dashboard.statusLine.Text; !strings.Contains(text, "Failed to get process list: permission denied (x3)") {
+		t.Errorf("Unexpected status line %q", text)
+	}
+}
+
+func TestDashboard_CollectionErrorInStatusLine(t *testing.T) {
+	dashboard := newPriorityDashboard(t, newFakeSource())
+	percent := dashboard.cpuCharts[0].Percent
+
+	dashboard.apply(system.Snapshot{Err: errors.New("failed to get CPU percent: no such file")})
+	if text := dashboard.statusLine.Text; !strings.Contains(text, "error") ||
+		!strings.Contains(text, "failed to get CPU percent: no such file") {
+		t.Errorf("Expected the collection error in the status line, got %q", text)
+	}
+	// Индикаторы и список остаются от предыдущего снимка
+	if dashboard.cpuCharts[0].Percent != percent || len(dashboard.processes) != 2 {
+		t.Errorf("Expected the previous snapshot to stay on screen, got cpu=%d processes=%v",
+			dashboard.cpuCharts[0].Percent, pids(dashboard.processes))
+	}
+}
+
+func TestDashboard_RunContinuesAfterCollectionError(t *testing.T) {
+	mockUI := NewMockUI()
+	dashboard, err := NewDashboardWithUI(mockUI)
+	if err != nil {
+		t.Fatalf("NewDashboardWithUI() вернул ошибку: %v", err)
+	}
+	dashboard.refreshInterval = 5 * time.Millisecond
+	var calls atomic.Int32
+	dashboard.collect = func(ctx context.Context) system.Snapshot {
+		calls.Add(1)
+		return system.Snapshot{Err: errors.New("failed to get memory info: busy")}
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- dashboard.Run()
+	}()
+	for deadline := time.Now().Add(time.Second); calls.Load() < 5; {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected collection to continue after an error")
+		}
+		select {
+		case err := <-done:
+			t.Fatalf("Run() exited on a collection error: %v", err)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	mockUI.events <- ui.Event{Type: ui.KeyboardEvent, ID: "q"}
+	if err := <-done; err != nil {
+		t.Errorf("Run() вернул ошибку: %v", err)
+	}
+	if !strings.Contains(dashboard.statusLine.Text, "failed to get memory info: busy") {
+		t.Errorf("Expected the collection error in the status line, got %q", dashboard.statusLine.Text)
+	}
+}
+
+func TestDashboard_StatusTimerArmedBeforeRun(t *testing.T) {
+	dashboard := newPriorityDashboard(t, newFakeSource())
+	dashboard.report(severityInfo, "config loaded")
+	// Сообщение до запуска Run тоже скрывается по таймеру
+	if !dashboard.statusTimer.Stop() {
+		t.Error("Expected the status timer to be armed by report")
+	}
+}
+
+func TestDashboard_StatusExpires(t *testing.T) {
+	dashboard := newPriorityDashboard(t, newFakeSource())
+	dashboard.report(severityWarning, "cgroup %s is not readable", "/sys/fs/cgroup")
+	if !strings.Contains(dashboard.statusLine.Text, "warning cgroup /sys/fs/cgroup is not readable") {
+		t.Errorf("Unexpected status line %q", dashboard.statusLine.Text)
+	}
+
+	dashboard.expireStatus()
+	if dashboard.statusLine.Text != "" || len(dashboard.messages) != 1 {
+		t.Errorf("Expected the line to clear and the history to keep the message, got %q, %v",
+			dashboard.statusLine.Text, dashboard.messages)
+	}
+	if severityError.timeout() <= severityInfo.timeout() {
+		t.Error("Expected errors to stay longer than info messages")
+	}
+
+	for i := 0; i < maxMessages+10; i++ {
+		dashboard.report(severityInfo, "message %d", i)
+	}
+	if len(dashboard.messages) != maxMessages || dashboard.messages[0].text != "message 10" {
+		t.Errorf("Expected history limited to the last %d messages, got %d starting with %q",
+			maxMessages, len(dashboard.messages), dashboard.messages[0].text)
+	}
+}
+
+func TestDashboard_MessageHistory(t *testing.T) {
+	dashboard := newPriorityDashboard(t, newFakeSource())
+	dashboard.handleKey("L")
+	if dashboard.history == nil || dashboard.history.Rows[0] != "No messages" {
+		t.Fatal("Expected an empty message history to open")
+	}
+	dashboard.handleKey("<Escape>")
+
+	dashboard.report(severityInfo, "first")
+	dashboard.report(severityError, "second")
+	dashboard.handleKey("L")
+	if len(dashboard.history.Rows) != 2 || dashboard.history.SelectedRow != 1 {
+		t.Fatalf("Expected the cursor on the last of 2 messages, got %v at %d",
+			dashboard.history.Rows, dashboard.history.SelectedRow)
+	}
+
+	// Клавиши прокручивают историю, а не список процессов
+	dashboard.handleKey("<Up>")
+	if dashboard.history.SelectedRow != 0 || dashboard.selectedRow != 0 {
+		t.Errorf("Expected <Up> to scroll the history, got row %d", dashboard.history.SelectedRow)
+	}
+	dashboard.report(severityInfo, "third")
+	if len(dashboard.history.Rows) != 3 || dashboard.history.SelectedRow != 0 {
+		t.Errorf("Expected new message appended without moving the cursor, got %v at %d",
+			dashboard.history.Rows, dashboard.history.SelectedRow)
+	}
+
+	dashboard.handleKey("L")
+	if dashboard.history != nil {
+		t.Error("Expected L to close the history")
+	}
+}
@@ -0,0 +1,255 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// summaryWidth - ширина окна с итогами действия над отмеченными процессами
+const summaryWidth = 72
+
+// isTagged сообщает, отмечен ли процесс. Отметка хранит время запуска, чтобы
+// не перейти на новый процесс, занявший PID завершившегося.
+func (d *Dashboard) isTagged(p system.ProcessInfo) bool {
+	start, ok := d.tagged[p.PID]
+	return ok && start.Equal(p.StartTime)
+}
+
+// setTag отмечает процесс или снимает с него отметку
+func (d *Dashboard) setTag(p system.ProcessInfo, tag bool) {
+	if tag {
+		d.tagged[p.PID] = p.StartTime
+	} else {
+		delete(d.tagged, p.PID)
+	}
+}
+
+// toggleTag переключает отметку выбранного процесса и переводит курсор на
+// следующую строку, чтобы отмечать процессы подряд
+func (d *Dashboard) toggleTag() {
+	p, ok := d.selectedProcess()
+	if !ok {
+		return
+	}
+	d.setTag(p, !d.isTagged(p))
+	d.selectRow(d.selectedRow + 1)
+	d.refreshProcessList()
+}
+
+// tagShown отмечает все процессы списка - подходящие под фильтр и параметры запуска
+func (d *Dashboard) tagShown() {
+	for _, p := range d.processes {
+		d.setTag(p, true)
+	}
+	d.refreshProcessList()
+}
+
+// tagSubtree отмечает выбранный процесс и всех его потомков, включая скрытые
+// фильтром и свернутые ветки
+func (d *Dashboard) tagSubtree() {
+	root, ok := d.selectedProcess()
+	if !ok {
+		return
+	}
+	children := make(map[int32][]system.ProcessInfo)
+	for _, p := range d.allProcesses {
+		if p.PPID != p.PID {
+			children[p.PPID] = append(children[p.PPID], p)
+		}
+	}
+	// Обход в ширину; visited защищает от циклов по PPID
+	visited := map[int32]bool{root.PID: true}
+	queue := []system.ProcessInfo{root}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		d.setTag(p, true)
+		for _, child := range children[p.PID] {
+			if !visited[child.PID] {
+				visited[child.PID] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+	d.refreshProcessList()
+}
+
+// untagAll снимает все отметки
+func (d *Dashboard) untagAll() {
+	clear(d.tagged)
+	d.refreshProcessList()
+}
+
+// pruneTags снимает отметки с завершившихся процессов
+func (d *Dashboard) pruneTags() {
+	if len(d.tagged) == 0 {
+		return
+	}
+	alive := make(map[int32]time.Time, len(d.tagged))
+	for _, p := range d.allProcesses {
+		if d.isTagged(p) {
+			alive[p.PID] = p.StartTime
+		}
+	}
+	d.tagged = alive
+}
+
+// actionTargets - процессы, над которыми выполняется действие, и признак, что
+// это отмеченные процессы, а не выбранный. Меню и диалоги запоминают цели при
+// открытии: пока они открыты, отмеченные процессы могут завершиться, и действие
+// не должно перейти на процесс под курсором, который никто не выбирал.
+type actionTargets struct {
+	processes []system.ProcessInfo
+	tagged    bool
+}
+
+// targets возвращает процессы, над которыми выполняется действие: отмеченные
+// в порядке списка или, если отметок нет, выбранный
+func (d *Dashboard) targets() actionTargets {
+	if len(d.tagged) == 0 {
+		if p, ok := d.selectedProcess(); ok {
+			return actionTargets{processes: []system.ProcessInfo{p}}
+		}
+		return actionTargets{}
+	}
+	targets := actionTargets{tagged: true}
+	for _, p := range d.allProcesses {
+		if d.isTagged(p) {
+			targets.processes = append(targets.processes, p)
+		}
+	}
+	return targets
+}
+
+// actionResult - итог действия над одним процессом
+type actionResult struct {
+	process system.ProcessInfo
+	err     error
+}
+
+// runAction выполняет action над каждым процессом из targets. Итог действия
+// над одним выбранным процессом показывается в строке состояния, а для
+// отмеченных процессов - еще и сводка с итогом по каждому PID. name описывает
+// действие: "send SIGTERM to", "renice".
+func (d *Dashboard) runAction(name string, targets actionTargets, action func(p system.ProcessInfo) error) {
+	results := make([]actionResult, len(targets.processes))
+	for i, p := range targets.processes {
+		results[i] = actionResult{process: p, err: action(p)}
+	}
+	if !targets.tagged {
+		for _, r := range results {
+			if r.err != nil {
+				d.report(severityError, "Failed to %s process %d: %v", name, r.process.PID, r.err)
+			} else {
+				d.report(severityInfo, "%s process %d (%s): ok", capitalize(name), r.process.PID, r.process.Name)
+			}
+		}
+		return
+	}
+	d.showSummary(name, results)
+}
+
+// showSummary показывает итоги действия над отмеченными процессами
+func (d *Dashboard) showSummary(name string, results []actionResult) {
+	failed := 0
+	rows := make([]string, len(results))
+	for i, r := range results {
+		status := "ok"
+		if r.err != nil {
+			failed++
+			status = sanitize(r.err.Error())
+		}
+		rows[i] = fmt.Sprintf("%7d %-15s %s", r.process.PID, fitText(r.process.Name, 15, false), status)
+	}
+
+	outcome := fmt.Sprintf("%s %d processes: %d ok, %d failed", capitalize(name), len(results), len(results)-failed, failed)
+	switch failed {
+	case 0:
+		d.report(severityInfo, "%s", outcome)
+	case len(results):
+		d.report(severityError, "%s", outcome)
+	default:
+		d.report(severityWarning, "%s", outcome)
+	}
+
+	summary := widgets.NewList()
+	summary.Title = outcome + " (Esc close)"
+	summary.Rows = rows
+	summary.TextStyle = ui.NewStyle(ui.ColorWhite)
+	summary.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ui.ColorYellow)
+	summary.WrapText = false
+	if d.options.Monochrome {
+		plain := ui.NewStyle(ui.ColorClear)
+		summary.BorderStyle = plain
+		summary.TitleStyle = plain
+		summary.TextStyle = plain
+		summary.SelectedRowStyle = ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierReverse)
+	}
+	d.summary = summary
+}
+
+// capitalize делает заглавной первую букву названия действия
+func capitalize(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// handleSummaryKey обрабатывает клавиши в окне итогов
+func (d *Dashboard) handleSummaryKey(id string) {
+	switch id {
+	case "<Escape>", "<Enter>", "<Left>", "q":
+		d.summary = nil
+	case "<Up>":
+		d.summary.ScrollUp()
+	case "<Down>":
+		d.summary.ScrollDown()
+	case "<PageUp>":
+		d.summary.ScrollPageUp()
+	case "<PageDown>":
+		d.summary.ScrollPageDown()
+	}
+}
+
+// updateSummaryPosition располагает окно итогов у курсора
+func (d *Dashboard) updateSummaryPosition() {
+	rect := d.menuRect(summaryWidth, len(d.summary.Rows))
+	d.summary.SetRect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y)
+}
+
+// tagStyle - оформление строк отмеченных процессов в разметке termui
+func (d *Dashboard) tagStyle() string {
+	if d.options.Monochrome {
+		return "mod:bold"
+	}
+	return "fg:yellow,mod:bold"
+}
+
+// styleRow выделяет строку списка разметкой termui. Разметка не экранируется,
+// поэтому строку с несбалансированными квадратными скобками termui разобрал бы
+// неверно - в ней скобки заменяются круглыми.
+func styleRow(row, style string) string {
+	depth := 0
+	for _, r := range row {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth < 0 {
+			break
+		}
+	}
+	if depth != 0 {
+		row = strings.NewReplacer("[", "(", "]", ")").Replace(row)
+	}
+	return "[" + row + "](" + style + ")"
+}
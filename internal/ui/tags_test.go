@@ -0,0 +1,252 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// newTagSource создает фиктивный источник с деревом процессов:
+// 1 init -> 10 nginx -> 11, 12 worker; 1 -> 20 sshd
+func newTagSource() *fakeSource {
+	source := newFakeSource()
+	source.processes = []system.ProcessInfo{
+		{PID: 1, Name: "init", CPU: 1},
+		{PID: 10, PPID: 1, Name: "nginx", CPU: 50},
+		{PID: 11, PPID: 10, Name: "worker", CPU: 40},
+		{PID: 12, PPID: 10, Name: "worker", CPU: 30},
+		{PID: 20, PPID: 1, Name: "sshd", CPU: 20},
+	}
+	return source
+}
+
+// taggedPIDs возвращает PID процессов, над которыми выполнится действие
+func taggedPIDs(d *Dashboard) []int32 {
+	return pids(d.targets().processes)
+}
+
+func TestDashboard_TagWithSpace(t *testing.T) {
+	dashboard := newPriorityDashboard(t, newFakeSource())
+
+	// Space отмечает процесс и переходит к следующему
+	dashboard.handleKey("<Space>")
+	if dashboard.selectedRow != 1 {
+		t.Errorf("Expected cursor to move down after tagging, got row %d", dashboard.selectedRow)
+	}
+	if !equalPIDs(taggedPIDs(dashboard), []int32{200}) {
+		t.Errorf("Expected PID 200 tagged, got %v", taggedPIDs(dashboard))
+	}
+	if row := dashboard.processList.Rows[0]; !strings.HasPrefix(row, "[") || !strings.HasSuffix(row, "](fg:yellow,mod:bold)") {
+		t.Errorf("Expected the tagged row to be highlighted, got %q", row)
+	}
+	if !strings.Contains(dashboard.processList.Title, "[tagged: 1]") {
+		t.Errorf("Expected tag count in title, got %q", dashboard.processList.Title)
+	}
+
+	dashboard.handleKey("<Up>")
+	dashboard.handleKey("<Space>")
+	if len(dashboard.tagged) != 0 {
+		t.Errorf("Expected second Space to untag, got %v", dashboard.tagged)
+	}
+}
+
+func TestDashboard_TagSubtreeAndFilter(t *testing.T) {
+	source := newTagSource()
+	dashboard := newSourceDashboard(t, source)
+
+	// Курсор на nginx (PID 10): отмечаются он и оба потомка
+	dashboard.handleKey("c")
+	if !equalPIDs(taggedPIDs(dashboard), []int32{10, 11, 12}) {
+		t.Errorf("Expected nginx subtree tagged, got %v", taggedPIDs(dashboard))
+	}
+
+	dashboard.handleKey("U")
+	if len(dashboard.tagged) != 0 {
+		t.Fatalf("Expected U to untag all, got %v", dashboard.tagged)
+	}
+
+	// * отмечает все процессы, подходящие под фильтр
+	dashboard.filterText = "worker"
+	dashboard.refreshProcessList()
+	dashboard.handleKey("*")
+	dashboard.filterText = ""
+	dashboard.refreshProcessList()
+	if !equalPIDs(taggedPIDs(dashboard), []int32{11, 12}) {
+		t.Errorf("Expected filtered processes tagged, got %v", taggedPIDs(dashboard))
+	}
+}
+
+func TestDashboard_TagsFollowProcessLifetime(t *testing.T) {
+	source := newTagSource()
+	dashboard := newSourceDashboard(t, source)
+	dashboard.handleKey("*")
+
+	// PID 11 завершился, PID 12 занял новый процесс
+	source.processes = append(source.processes[:2:2], system.ProcessInfo{
+		PID: 12, PPID: 10, Name: "worker", StartTime: time.Unix(1000, 0),
+	}, source.processes[4])
+	if err := dashboard.update(); err != nil {
+		t.Fatalf("update() вернул ошибку: %v", err)
+	}
+	if !equalPIDs(taggedPIDs(dashboard), []int32{10, 20, 1}) {
+		t.Errorf("Expected tags of exited and reused PIDs to be dropped, got %v", taggedPIDs(dashboard))
+	}
+}
+
+func TestDashboard_BulkSignalSummary(t *testing.T) {
+	source := newTagSource()
+	dashboard := newSourceDashboard(t, source)
+	dashboard.handleKey("c")
+
+	dashboard.handleKey("<Right>")
+	dashboard.handleKey("<Enter>")
+	if len(source.signals) != 3 {
+		t.Fatalf("Expected a signal per tagged process, got %v", source.signals)
+	}
+	for _, s := range source.signals {
+		if s.sig != syscall.SIGTERM {
+			t.Errorf("Expected SIGTERM, got %v", s)
+		}
+	}
+	if dashboard.summary == nil {
+		t.Fatal("Expected a result summary for tagged processes")
+	}
+	if !strings.HasPrefix(dashboard.summary.Title, "Send SIGTERM to 3 processes: 3 ok, 0 failed") {
+		t.Errorf("Unexpected summary title %q", dashboard.summary.Title)
+	}
+	if len(dashboard.summary.Rows) != 3 || !strings.Contains(dashboard.summary.Rows[0], "     10 nginx") {
+		t.Errorf("Expected a row per PID, got %q", dashboard.summary.Rows)
+	}
+
+	// Пока сводка открыта, клавиши не доходят до списка
+	dashboard.handleKey("q")
+	if dashboard.summary != nil {
+		t.Error("Expected q to close the summary")
+	}
+}
+
+func TestDashboard_TargetsCapturedOnOpen(t *testing.T) {
+	tests := []struct {
+		name    string
+		open    []string // клавиши, открывающие меню или диалог
+		confirm []string // клавиши, подтверждающие действие
+		changed func(source *fakeSource) []int32
+	}{
+		{"Signal", []string{"<Right>"}, []string{"<Enter>"}, func(source *fakeSource) []int32 {
+			var changed []int32
+			for _, s := range source.signals {
+				changed = append(changed, s.pid)
+			}
+			return changed
+		}},
+		{"I/O priority", []string{"i"}, []string{"<Enter>"}, func(source *fakeSource) []int32 {
+			var changed []int32
+			for pid := range source.ioPriorities {
+				changed = append(changed, pid)
+			}
+			return changed
+		}},
+		{"Affinity", []string{"a"}, []string{"a", "<Enter>"}, func(source *fakeSource) []int32 {
+			var changed []int32
+			for pid, cpus := range source.affinity {
+				if len(cpus) == 3 {
+					changed = append(changed, pid)
+				}
+			}
+			return changed
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := newTagSource()
+			source.affinity = map[int32][]int{11: {1}}
+			dashboard := newSourceDashboard(t, source)
+			// Отмечен nginx (PID 10), курсор переходит на worker (PID 11)
+			dashboard.handleKey("<Space>")
+			for _, key := range tt.open {
+				dashboard.handleKey(key)
+			}
+
+			// Пока меню открыто, отмеченный процесс завершается
+			source.processes = append(source.processes[:1:1], source.processes[2:]...)
+			if err := dashboard.update(); err != nil {
+				t.Fatalf("update() вернул ошибку: %v", err)
+			}
+			if len(dashboard.tagged) != 0 {
+				t.Fatalf("Expected the tag of the exited process to be dropped, got %v", dashboard.tagged)
+			}
+
+			for _, key := range tt.confirm {
+				dashboard.handleKey(key)
+			}
+			// Действие применяется к процессам на момент открытия, а не к
+			// процессу под курсором, и итог показывается сводкой
+			if got := tt.changed(source); !equalPIDs(got, []int32{10}) {
+				t.Errorf("Expected only the tagged PID 10 to be changed, got %v", got)
+			}
+			if dashboard.summary == nil {
+				t.Error("Expected a summary for an action on tagged processes")
+			}
+		})
+	}
+}
+
+func TestDashboard_BulkReniceErrors(t *testing.T) {
+	source := newTagSource()
+	source.priorityErr = errors.New("permission denied")
+	dashboard := newSourceDashboard(t, source)
+	dashboard.handleKey("<Space>")
+	dashboard.handleKey("<Space>")
+
+	dashboard.handleKey("<F8>")
+	if dashboard.summary == nil {
+		t.Fatal("Expected a result summary for tagged processes")
+	}
+	if !strings.Contains(dashboard.summary.Title, "0 ok, 2 failed") {
+		t.Errorf("Expected both failures counted, got %q", dashboard.summary.Title)
+	}
+	for _, row := range dashboard.summary.Rows {
+		if !strings.HasSuffix(row, "permission denied") {
+			t.Errorf("Expected the error in the row, got %q", row)
+		}
+	}
+}
+
+func TestDashboard_BulkAffinity(t *testing.T) {
+	source := newTagSource()
+	source.affinity = map[int32][]int{10: {0, 1, 2}}
+	dashboard := newSourceDashboard(t, source)
+	dashboard.handleKey("c")
+
+	// Снимаем ядро 0 и применяем к поддереву
+	dashboard.handleKey("a")
+	dashboard.handleKey("<Space>")
+	dashboard.handleKey("<Enter>")
+	for _, pid := range []int32{10, 11, 12} {
+		if cpus := source.affinity[pid]; len(cpus) != 2 || cpus[0] != 1 {
+			t.Errorf("Expected CPUs 1-2 for PID %d, got %v", pid, cpus)
+		}
+	}
+	if _, ok := source.affinity[20]; ok {
+		t.Error("Expected untagged processes to be left alone")
+	}
+}
+
+func TestStyleRow(t *testing.T) {
+	tests := []struct{ row, want string }{
+		{"  1 init", "[  1 init](mod:bold)"},
+		{"  2 [kthreadd]", "[  2 [kthreadd]](mod:bold)"},
+		{"  3 bad] name", "[  3 bad) name](mod:bold)"},
+		{"  4 ][", "[  4 )(](mod:bold)"},
+	}
+	for _, tt := range tests {
+		if got := styleRow(tt.row, "mod:bold"); got != tt.want {
+			t.Errorf("styleRow(%q) = %q, want %q", tt.row, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"github.com/bonefabric/htop/internal/system"
+)
+
+// buildTree раскладывает процессы в дерево по PPID и возвращает их в порядке
+// обхода вместе с префиксами веток для отображения. Порядок среди соседних
+// узлов сохраняется таким, каким он был во входном (уже отсортированном) срезе.
+// Потомки свернутых узлов в результат не попадают.
+func buildTree(processes []system.ProcessInfo, collapsed map[int32]bool) ([]system.ProcessInfo, []string) {
+	present := make(map[int32]bool, len(processes))
+	for _, p := range processes {
+		present[p.PID] = true
+	}
+
+	// Процесс без известного родителя становится корнем
+	var roots []system.ProcessInfo
+	children := make(map[int32][]system.ProcessInfo)
+	for _, p := range processes {
+		if p.PPID == p.PID || !present[p.PPID] {
+			roots = append(roots, p)
+		} else {
+			children[p.PPID] = append(children[p.PPID], p)
+		}
+	}
+
+	result := make([]system.ProcessInfo, 0, len(processes))
+	prefixes := make([]string, 0, len(processes))
+	visited := make(map[int32]bool, len(processes))
+
+	// hidden - узел находится внутри свернутой ветки: обходим его, чтобы
+	// отметить посещенным, но не выводим
+	var walk func(p system.ProcessInfo, indent string, last, root, hidden bool)
+	walk = func(p system.ProcessInfo, indent string, last, root, hidden bool) {
+		if visited[p.PID] {
+			return
+		}
+		visited[p.PID] = true
+		if hidden {
+			for _, child := range children[p.PID] {
+				walk(child, "", false, false, true)
+			}
+			return
+		}
+
+		hasChildren := len(children[p.PID]) > 0
+		isCollapsed := hasChildren && collapsed[p.PID]
+
+		var prefix, childIndent string
+		if !root {
+			branch, cont := "├", "│  "
+			if last {
+				branch, cont = "└", "   "
+			}
+			mark := "─ "
+			if isCollapsed {
+				mark = "+ "
+			}
+			prefix = indent + branch + mark
+			childIndent = indent + cont
+		} else if isCollapsed {
+			prefix = "+ "
+		}
+
+		result = append(result, p)
+		prefixes = append(prefixes, prefix)
+
+		kids := children[p.PID]
+		for i, child := range kids {
+			walk(child, childIndent, i == len(kids)-1, false, isCollapsed)
+		}
+	}
+
+	for _, p := range roots {
+		walk(p, "", true, true, false)
+	}
+
+	// Процессы, образующие цикл по PPID, не достижимы из корней - выводим их как корни
+	for _, p := range processes {
+		if !visited[p.PID] {
+			walk(p, "", true, true, false)
+		}
+	}
+
+	return result, prefixes
+}
+
+// toggleTreeView включает или выключает отображение дерева процессов
+func (d *Dashboard) toggleTreeView() {
+	d.treeView = !d.treeView
+	d.config.TreeView = d.treeView
+	d.refreshProcessList()
+	d.saveConfig()
+}
+
+// setCollapsed сворачивает или разворачивает ветку выбранного процесса
+func (d *Dashboard) setCollapsed(collapse bool) {
+	if !d.treeView || d.selectedRow >= len(d.processes) {
+		return
+	}
+	pid := d.processes[d.selectedRow].PID
+	if collapse {
+		d.collapsed[pid] = true
+	} else {
+		delete(d.collapsed, pid)
+	}
+	d.refreshProcessList()
+}
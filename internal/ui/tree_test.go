@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bonefabric/htop/internal/system"
+)
+
+func treeProcesses() []system.ProcessInfo {
+	return []system.ProcessInfo{
+		{PID: 1, PPID: 0, Name: "init", CPU: 0.1},
+		{PID: 100, PPID: 1, Name: "nginx", CPU: 1.0},
+		{PID: 101, PPID: 100, Name: "nginx-worker", CPU: 20.0},
+		{PID: 102, PPID: 100, Name: "nginx-worker", CPU: 30.0},
+		{PID: 200, PPID: 1, Name: "sshd", CPU: 0.5},
+		{PID: 300, PPID: 999, Name: "orphan", CPU: 5.0},
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	processes := treeProcesses()
+	sortProcesses(processes, SortByCPU, true)
+
+	result, prefixes := buildTree(processes, map[int32]bool{})
+
+	// Корни и соседи идут в порядке сортировки по CPU
+	expected := []int32{300, 1, 100, 102, 101, 200}
+	if got := pids(result); !equalPIDs(got, expected) {
+		t.Fatalf("buildTree() order = %v, want %v", got, expected)
+	}
+
+	expectedPrefixes := []string{"", "", "├─ ", "│  ├─ ", "│  └─ ", "└─ "}
+	for i, prefix := range expectedPrefixes {
+		if prefixes[i] != prefix {
+			t.Errorf("Prefix for PID %d = %q, want %q", result[i].PID, prefixes[i], prefix)
+		}
+	}
+}
+
+func TestBuildTree_Collapsed(t *testing.T) {
+	processes := treeProcesses()
+	sortProcesses(processes, SortByPID, false)
+
+	result, prefixes := buildTree(processes, map[int32]bool{100: true})
+
+	expected := []int32{1, 100, 200, 300}
+	if got := pids(result); !equalPIDs(got, expected) {
+		t.Fatalf("buildTree() with collapsed node = %v, want %v", got, expected)
+	}
+	if prefixes[1] != "├+ " {
+		t.Errorf("Expected collapsed marker for PID 100, got %q", prefixes[1])
+	}
+}
+
+func TestBuildTree_Cycle(t *testing.T) {
+	processes := []system.ProcessInfo{
+		{PID: 10, PPID: 20, Name: "a"},
+		{PID: 20, PPID: 10, Name: "b"},
+		{PID: 30, PPID: 30, Name: "self"},
+	}
+
+	result, _ := buildTree(processes, map[int32]bool{})
+	if len(result) != len(processes) {
+		t.Errorf("Expected all %d processes in tree, got %v", len(processes), pids(result))
+	}
+}
+
+func TestDashboard_TreeView(t *testing.T) {
+	dashboard, err := NewDashboardWithUI(NewMockUI())
+	if err != nil {
+		t.Fatalf("Failed to create dashboard: %v", err)
+	}
+	dashboard.allProcesses = treeProcesses()
+	dashboard.refreshProcessList()
+
+	dashboard.handleKey("t")
+	if !dashboard.treeView {
+		t.Fatal("Expected 't' to enable tree view")
+	}
+	if !strings.Contains(dashboard.processList.Title, "tree") {
+		t.Errorf("Expected tree mode in title, got %q", dashboard.processList.Title)
+	}
+
+	// Курсор следует за выбранным до включения дерева процессом (nginx-worker 102)
+	if dashboard.processes[dashboard.selectedRow].PID != 102 {
+		t.Errorf("Expected selection to follow PID 102, got %d", dashboard.processes[dashboard.selectedRow].PID)
+	}
+
+	// Поднимаемся на nginx и сворачиваем его ветку
+	dashboard.handleKey("<Up>")
+	dashboard.handleKey("-")
+	if len(dashboard.processes) != 4 {
+		t.Errorf("Expected workers to be hidden, got %v", pids(dashboard.processes))
+	}
+	if !strings.Contains(dashboard.processList.Rows[dashboard.selectedRow], "+ nginx") {
+		t.Errorf("Expected collapsed marker in row, got %q", dashboard.processList.Rows[dashboard.selectedRow])
+	}
+
+	dashboard.handleKey("+")
+	if len(dashboard.processes) != 6 {
+		t.Errorf("Expected workers to be visible again, got %v", pids(dashboard.processes))
+	}
+	if dashboard.processes[dashboard.selectedRow].PID != 100 {
+		t.Errorf("Expected selection to stay on PID 100, got %d", dashboard.processes[dashboard.selectedRow].PID)
+	}
+
+	dashboard.handleKey("t")
+	if dashboard.treeView || dashboard.treePrefixes != nil {
+		t.Error("Expected 't' to disable tree view")
+	}
+}